@@ -0,0 +1,634 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	iocImportPath   = "github.com/josephsalimin/go-simple-ioc/ioc"
+	generatedHeader = "// Code generated by iocgen. DO NOT EDIT."
+	structTagKey    = "ioc"
+	defaultAlias    = "default"
+	contextLabel    = "context.Context"
+	errorLabel      = "error"
+	configPrefix    = "config:"
+)
+
+var (
+	errUnsupported        = errors.New("binding is not supported")
+	errNotRegistered      = errors.New("dependency is not bound")
+	errCircularDependency = errors.New("circular dependency is detected")
+)
+
+type fileInfo struct {
+	// imports is map of package name used in the file to its import path.
+	imports map[string]string
+	// iocName is name used to refer ioc package in the file, empty if not imported.
+	iocName string
+}
+
+type funcInfo struct {
+	name string
+	// params is type of each parameter, expanded for parameters sharing the same type.
+	params   []ast.Expr
+	result   ast.Expr
+	hasError bool
+	file     *fileInfo
+}
+
+type fieldInfo struct {
+	label string
+	tag   string
+}
+
+type dependency struct {
+	label     string
+	alias     string
+	isContext bool
+}
+
+type binding struct {
+	label       string
+	alias       string
+	isSingleton bool
+	fn          *funcInfo
+	meta        string
+	pos         token.Position
+	deps        []dependency
+	// method is name of generated method that returns the instance, field is name of its singleton cache.
+	method string
+	field  string
+}
+
+type analyzer struct {
+	fset     *token.FileSet
+	pkgName  string
+	funcs    map[string]*funcInfo
+	structs  map[string][]fieldInfo
+	bindings map[[2]string]*binding
+}
+
+func exprString(expr ast.Expr) string {
+	return types.ExprString(expr)
+}
+
+func parsePackage(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected 1 package in %v, but instead got %v", dir, len(pkgs))
+	}
+
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		names := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			file := pkg.Files[name]
+			// Skips previously generated file, so it does not affect the analysis.
+			if len(file.Comments) > 0 && strings.HasPrefix(file.Comments[0].Text(), generatedHeader[3:]) {
+				continue
+			}
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func newFileInfo(file *ast.File) *fileInfo {
+	info := &fileInfo{imports: map[string]string{}}
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		info.imports[name] = importPath
+		if importPath == iocImportPath {
+			info.iocName = name
+		}
+	}
+
+	return info
+}
+
+func (a *analyzer) collectDecls(file *ast.File, info *fileInfo) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv != nil {
+				continue
+			}
+			fn := &funcInfo{name: decl.Name.Name, file: info}
+			for _, field := range decl.Type.Params.List {
+				for i := 0; i < len(field.Names) || i == 0; i++ {
+					fn.params = append(fn.params, field.Type)
+				}
+			}
+			if results := decl.Type.Results; results != nil && len(results.List) > 0 {
+				fn.result = results.List[0].Type
+				fn.hasError = results.NumFields() == 2 && exprString(results.List[len(results.List)-1].Type) == errorLabel
+			}
+			a.funcs[fn.name] = fn
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+
+				var fields []fieldInfo
+				for _, field := range structType.Fields.List {
+					tag := ""
+					if field.Tag != nil {
+						tag, _ = strconv.Unquote(field.Tag.Value)
+					}
+					for i := 0; i < len(field.Names) || i == 0; i++ {
+						fields = append(fields, fieldInfo{label: exprString(field.Type), tag: tag})
+					}
+				}
+				a.structs[typeSpec.Name.Name] = fields
+			}
+		}
+	}
+}
+
+// bindCall checks whether call is a bind call, and returns whether the binding is singleton.
+func bindCall(call *ast.CallExpr, info *fileInfo) (isBind bool, isSingleton bool, err error) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false, false, nil
+	}
+
+	switch sel.Sel.Name {
+	case "BindSingleton", "MustBindSingleton":
+		return true, true, nil
+	case "BindTransient", "MustBindTransient":
+		return true, false, nil
+	case "BindScoped", "MustBindScoped":
+		return false, false, fmt.Errorf("scoped %w", errUnsupported)
+	}
+
+	// Declarative provider set can only be recognized from ioc package itself.
+	if x, ok := sel.X.(*ast.Ident); !ok || info.iocName == "" || x.Name != info.iocName {
+		return false, false, nil
+	}
+	switch sel.Sel.Name {
+	case "Singleton":
+		return true, true, nil
+	case "Transient":
+		return true, false, nil
+	case "Scoped":
+		return false, false, fmt.Errorf("scoped %w", errUnsupported)
+	}
+
+	return false, false, nil
+}
+
+func (a *analyzer) applyOption(b *binding, expr ast.Expr) error {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return fmt.Errorf("option %v %w", exprString(expr), errUnsupported)
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return fmt.Errorf("option %v %w", exprString(expr), errUnsupported)
+	}
+
+	switch sel.Sel.Name {
+	case "WithBindAlias":
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return fmt.Errorf("alias must be string literal, but instead got %v", exprString(call.Args[0]))
+		}
+		b.alias, _ = strconv.Unquote(lit.Value)
+	case "WithBindMeta":
+		var typeExpr ast.Expr
+		switch arg := call.Args[0].(type) {
+		case *ast.UnaryExpr:
+			if lit, ok := arg.X.(*ast.CompositeLit); ok && arg.Op == token.AND {
+				typeExpr = lit.Type
+			}
+		case *ast.CallExpr:
+			if ident, ok := arg.Fun.(*ast.Ident); ok && ident.Name == "new" && len(arg.Args) == 1 {
+				typeExpr = arg.Args[0]
+			}
+		}
+		ident, ok := typeExpr.(*ast.Ident)
+		if !ok {
+			return fmt.Errorf("meta must be &T{} or new(T) of local type, but instead got %v",
+				exprString(call.Args[0]))
+		}
+		b.meta = ident.Name
+	default:
+		return fmt.Errorf("option %v %w", exprString(expr), errUnsupported)
+	}
+
+	return nil
+}
+
+func (a *analyzer) collectBindings(file *ast.File, info *fileInfo) error {
+	var err error
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+
+		isBind, isSingleton, bindErr := bindCall(call, info)
+		if bindErr != nil {
+			err = fmt.Errorf("%v: %w", a.fset.Position(call.Pos()), bindErr)
+			return false
+		}
+		if !isBind || len(call.Args) == 0 {
+			return true
+		}
+
+		pos := a.fset.Position(call.Pos())
+		ident, ok := call.Args[0].(*ast.Ident)
+		if !ok || a.funcs[ident.Name] == nil {
+			err = fmt.Errorf("%v: resolve function must be a top level function of the package, but instead got %v",
+				pos, exprString(call.Args[0]))
+			return false
+		}
+
+		fn := a.funcs[ident.Name]
+		if fn.result == nil {
+			err = fmt.Errorf("%v: resolve function %v does not return anything", pos, fn.name)
+			return false
+		}
+		if _, ok := a.structs[exprString(fn.result)]; ok {
+			err = fmt.Errorf("%v: expected pointer or interface, but instead got %v", pos, exprString(fn.result))
+			return false
+		}
+
+		b := &binding{label: exprString(fn.result), alias: defaultAlias, isSingleton: isSingleton, fn: fn, pos: pos}
+		for _, opt := range call.Args[1:] {
+			if optErr := a.applyOption(b, opt); optErr != nil {
+				err = fmt.Errorf("%v: %w", pos, optErr)
+				return false
+			}
+		}
+		// Struct of pointer result is used when meta is not given, same as the container.
+		if star, ok := fn.result.(*ast.StarExpr); ok && b.meta == "" {
+			if ident, ok := star.X.(*ast.Ident); ok {
+				b.meta = ident.Name
+			}
+		}
+		if b.deps, err = a.dependencies(fn, b.meta); err != nil {
+			err = fmt.Errorf("%v: %w", pos, err)
+			return false
+		}
+		a.bindings[[2]string{b.label, b.alias}] = b
+
+		return true
+	})
+
+	return err
+}
+
+// dependencies follows getDependencies of the container: parameters are matched to fields of meta struct with
+// the same type in order, and the ioc tag of the field becomes the alias. Config dependencies are not supported,
+// as the generated graph has no config provider.
+func (a *analyzer) dependencies(fn *funcInfo, meta string) ([]dependency, error) {
+	labelMap := map[string][]int{}
+	labelCtrMap := map[string]int{}
+	deps := make([]dependency, len(fn.params))
+	for idx, param := range fn.params {
+		label := exprString(param)
+		if label == contextLabel {
+			deps[idx] = dependency{label: label, isContext: true}
+			continue
+		}
+		labelMap[label] = append(labelMap[label], idx)
+	}
+
+	for _, field := range a.structs[meta] {
+		inIdxList, ok := labelMap[field.label]
+		if !ok || labelCtrMap[field.label] >= len(inIdxList) {
+			continue
+		}
+		inIdx := inIdxList[labelCtrMap[field.label]]
+		labelCtrMap[field.label]++
+
+		tag := reflect.StructTag(field.tag).Get(structTagKey)
+		if strings.HasPrefix(tag, configPrefix) {
+			return nil, fmt.Errorf("config dependency %v of %v %w", tag, field.label, errUnsupported)
+		}
+		alias := strings.Split(tag, ",")[0]
+		if alias == "" {
+			alias = defaultAlias
+		}
+		deps[inIdx] = dependency{label: field.label, alias: alias}
+	}
+
+	// Leftover will be set to default
+	for label, inIdxList := range labelMap {
+		for i := labelCtrMap[label]; i < len(inIdxList); i++ {
+			deps[inIdxList[i]] = dependency{label: label, alias: defaultAlias}
+		}
+	}
+
+	return deps, nil
+}
+
+func exportedName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func baseName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return baseName(expr.X)
+	case *ast.Ident:
+		return exportedName(expr.Name)
+	case *ast.SelectorExpr:
+		return exportedName(exprString(expr.X)) + exportedName(expr.Sel.Name)
+	}
+
+	return "Binding"
+}
+
+func (a *analyzer) sortedBindings() []*binding {
+	bindings := make([]*binding, 0, len(a.bindings))
+	for _, b := range a.bindings {
+		bindings = append(bindings, b)
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].label != bindings[j].label {
+			return bindings[i].label < bindings[j].label
+		}
+		return bindings[i].alias < bindings[j].alias
+	})
+
+	return bindings
+}
+
+func nameBindings(bindings []*binding) {
+	used := map[string]bool{}
+	for _, b := range bindings {
+		name := baseName(b.fn.result)
+		if b.alias != defaultAlias {
+			name += exportedName(b.alias)
+		}
+		method := name
+		for i := 2; used[method] || method == "Build"; i++ {
+			method = name + strconv.Itoa(i)
+		}
+		used[method] = true
+
+		b.method = method
+		b.field = strings.ToLower(method[:1]) + method[1:]
+	}
+}
+
+// buildOrder returns singleton bindings ordered by their dependencies, and checks every dependency is bound and
+// not circular.
+func (a *analyzer) buildOrder(bindings []*binding) ([]*binding, error) {
+	const (
+		visiting = iota + 1
+		done
+	)
+	states := map[*binding]int{}
+	var order []*binding
+	var visit func(b *binding, path []string) error
+	visit = func(b *binding, path []string) error {
+		path = append(path, b.label+"#"+b.alias)
+		switch states[b] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%v: %v: %w", b.pos, strings.Join(path, " -> "), errCircularDependency)
+		}
+
+		states[b] = visiting
+		for _, dep := range b.deps {
+			if dep.isContext {
+				continue
+			}
+			depBinding, ok := a.bindings[[2]string{dep.label, dep.alias}]
+			if !ok {
+				return fmt.Errorf("%v: label %v with alias %v: %w", b.pos, dep.label, dep.alias, errNotRegistered)
+			}
+			if err := visit(depBinding, path); err != nil {
+				return err
+			}
+		}
+		states[b] = done
+		if b.isSingleton {
+			order = append(order, b)
+		}
+
+		return nil
+	}
+
+	for _, b := range bindings {
+		if err := visit(b, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// collectImports returns imports needed by result types of bindings, ordered by import path.
+func collectImports(bindings []*binding) ([][2]string, error) {
+	imports := map[string]string{"context": "context"}
+	for _, b := range bindings {
+		var err error
+		ast.Inspect(b.fn.result, func(node ast.Node) bool {
+			sel, ok := node.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			x, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			importPath, ok := b.fn.file.imports[x.Name]
+			if !ok {
+				err = fmt.Errorf("%v: can't find import of %v", b.pos, x.Name)
+				return false
+			}
+			if existing, ok := imports[x.Name]; ok && existing != importPath {
+				err = fmt.Errorf("%v: package name %v refers to both %v and %v", b.pos, x.Name, existing, importPath)
+				return false
+			}
+			imports[x.Name] = importPath
+
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	list := make([][2]string, 0, len(imports))
+	for name, importPath := range imports {
+		list = append(list, [2]string{name, importPath})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i][1] < list[j][1] })
+
+	return list, nil
+}
+
+func writeMethod(buf *bytes.Buffer, typeName string, b *binding, bindings map[[2]string]*binding) {
+	result := exprString(b.fn.result)
+	lifetime := "transient"
+	if b.isSingleton {
+		lifetime = "singleton"
+	}
+
+	fmt.Fprintf(buf, "// %v returns %v with alias %v, bound as %v using %v.\n",
+		b.method, result, b.alias, lifetime, b.fn.name)
+	fmt.Fprintf(buf, "func (g *%v) %v(ctx context.Context) (%v, error) {\n", typeName, b.method, result)
+	if b.isSingleton {
+		fmt.Fprintf(buf, "if g.%v != nil {\nreturn g.%v, nil\n}\n", b.field, b.field)
+	}
+
+	args := make([]string, 0, len(b.deps))
+	for idx, dep := range b.deps {
+		if dep.isContext {
+			args = append(args, "ctx")
+			continue
+		}
+		arg := "arg" + strconv.Itoa(idx)
+		args = append(args, arg)
+		fmt.Fprintf(buf, "%v, err := g.%v(ctx)\nif err != nil {\nreturn nil, err\n}\n",
+			arg, bindings[[2]string{dep.label, dep.alias}].method)
+	}
+	buf.WriteString("if err := ctx.Err(); err != nil {\nreturn nil, err\n}\n")
+
+	call := fmt.Sprintf("%v(%v)", b.fn.name, strings.Join(args, ", "))
+	if b.fn.hasError {
+		fmt.Fprintf(buf, "instance, err := %v\nif err != nil {\nreturn nil, err\n}\n", call)
+	} else {
+		fmt.Fprintf(buf, "instance := %v\n", call)
+	}
+	if b.isSingleton {
+		fmt.Fprintf(buf, "g.%v = instance\n", b.field)
+	}
+	buf.WriteString("return instance, nil\n}\n\n")
+}
+
+func (a *analyzer) write(typeName string) ([]byte, error) {
+	bindings := a.sortedBindings()
+	nameBindings(bindings)
+	order, err := a.buildOrder(bindings)
+	if err != nil {
+		return nil, err
+	}
+	imports, err := collectImports(bindings)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v\n\npackage %v\n\nimport (\n", generatedHeader, a.pkgName)
+	for _, imp := range imports {
+		if path.Base(imp[1]) == imp[0] {
+			fmt.Fprintf(&buf, "%q\n", imp[1])
+		} else {
+			fmt.Fprintf(&buf, "%v %q\n", imp[0], imp[1])
+		}
+	}
+	buf.WriteString(")\n\n")
+
+	fmt.Fprintf(&buf, "// %v constructs bound dependencies without reflection.\n", typeName)
+	fmt.Fprintf(&buf, "// It is not safe for concurrent use until Build returns.\n")
+	fmt.Fprintf(&buf, "type %v struct {\n", typeName)
+	for _, b := range order {
+		fmt.Fprintf(&buf, "%v %v\n", b.field, exprString(b.fn.result))
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&buf, "// New%v creates empty %v, instances are created when requested or when Build is called.\n",
+		typeName, typeName)
+	fmt.Fprintf(&buf, "func New%v() *%v {\nreturn &%v{}\n}\n\n", typeName, typeName, typeName)
+
+	fmt.Fprintf(&buf, "// Build constructs all singletons in dependency order.\n")
+	fmt.Fprintf(&buf, "func (g *%v) Build(ctx context.Context) error {\n", typeName)
+	for _, b := range order {
+		fmt.Fprintf(&buf, "if _, err := g.%v(ctx); err != nil {\nreturn err\n}\n", b.method)
+	}
+	buf.WriteString("return nil\n}\n\n")
+
+	for _, b := range bindings {
+		writeMethod(&buf, typeName, b, a.bindings)
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// generate analyses package in dir and returns source code of the generated graph.
+func generate(dir, typeName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	files, err := parsePackage(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &analyzer{
+		fset:     fset,
+		funcs:    map[string]*funcInfo{},
+		structs:  map[string][]fieldInfo{},
+		bindings: map[[2]string]*binding{},
+	}
+	infos := make([]*fileInfo, len(files))
+	for idx, file := range files {
+		a.pkgName = file.Name.Name
+		infos[idx] = newFileInfo(file)
+		a.collectDecls(file, infos[idx])
+	}
+	for idx, file := range files {
+		if err := a.collectBindings(file, infos[idx]); err != nil {
+			return nil, err
+		}
+	}
+	if len(a.bindings) == 0 {
+		return nil, fmt.Errorf("can't find any binding in %v", dir)
+	}
+
+	return a.write(typeName)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func testGenerateSource(t *testing.T, src string) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "iocgen")
+	if err != nil {
+		t.Fatalf("failed to create dir, err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write source, err: %v", err)
+	}
+
+	return generate(dir, "Graph")
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generate graph from bindings and provider set", func(t *testing.T) {
+		dir := filepath.Join("testdata", "basic")
+		golden := filepath.Join(dir, "ioc_gen.go.golden")
+
+		src, err := generate(dir, "Graph")
+		if !assert.NoError(t, err) {
+			return
+		}
+		if *update {
+			if err := ioutil.WriteFile(golden, src, 0644); err != nil {
+				t.Fatalf("failed to update golden file, err: %v", err)
+			}
+		}
+
+		expected, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("failed to read golden file, err: %v", err)
+		}
+		assert.Equal(t, string(expected), string(src))
+	})
+
+	t.Run("generate graph with missing dependencies", func(t *testing.T) {
+		_, err := generate(filepath.Join("testdata", "missing"), "Graph")
+		assert.True(t, errors.Is(err, errNotRegistered))
+	})
+
+	t.Run("generate graph with circular dependencies", func(t *testing.T) {
+		_, err := testGenerateSource(t, `package main
+
+import "github.com/josephsalimin/go-simple-ioc/ioc"
+
+type A struct{}
+
+type B struct{}
+
+func NewA(b *B) *A { return &A{} }
+
+func NewB(a *A) *B { return &B{} }
+
+var Providers = []ioc.Binding{ioc.Singleton(NewA), ioc.Transient(NewB)}
+`)
+		assert.True(t, errors.Is(err, errCircularDependency))
+	})
+
+	t.Run("generate graph with function literal", func(t *testing.T) {
+		_, err := testGenerateSource(t, `package main
+
+import "github.com/josephsalimin/go-simple-ioc/ioc"
+
+type A struct{}
+
+func main() {
+	ioc.MustBindSingleton(func() *A { return &A{} })
+}
+`)
+		assert.Error(t, err)
+	})
+
+	t.Run("generate graph with scoped binding", func(t *testing.T) {
+		_, err := testGenerateSource(t, `package main
+
+import "github.com/josephsalimin/go-simple-ioc/ioc"
+
+type A struct{}
+
+func NewA() *A { return &A{} }
+
+func main() {
+	ioc.MustBindScoped(NewA)
+}
+`)
+		assert.True(t, errors.Is(err, errUnsupported))
+	})
+
+	t.Run("generate graph with config dependency", func(t *testing.T) {
+		_, err := testGenerateSource(t, `package main
+
+import "github.com/josephsalimin/go-simple-ioc/ioc"
+
+type A struct {
+	port int `+"`ioc:\"config:db.port,default=5432\"`"+`
+}
+
+func NewA(port int) *A { return &A{port: port} }
+
+var Providers = []ioc.Binding{ioc.Singleton(NewA)}
+`)
+		assert.True(t, errors.Is(err, errUnsupported))
+		assert.Contains(t, err.Error(), "config dependency config:db.port,default=5432 of int")
+	})
+
+	t.Run("generate graph with non pointer result", func(t *testing.T) {
+		_, err := testGenerateSource(t, `package main
+
+import "github.com/josephsalimin/go-simple-ioc/ioc"
+
+type A struct{}
+
+func NewA() A { return A{} }
+
+var Providers = []ioc.Binding{ioc.Singleton(NewA)}
+`)
+		assert.Error(t, err)
+	})
+}
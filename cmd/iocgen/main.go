@@ -0,0 +1,36 @@
+// Command iocgen generates reflection-free wiring from ioc bindings.
+//
+// It statically analyses calls to BindSingleton / BindTransient (including the Must variants) and the declarative
+// ioc.Singleton / ioc.Transient provider set of a package, then writes a graph type that constructs every binding
+// in dependency order with plain function calls, following the same alias and ioc tag rules as the container.
+//
+// Usage with go generate:
+//
+//	//go:generate go run github.com/josephsalimin/go-simple-ioc/cmd/iocgen -type Graph
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package to analyse")
+	output := flag.String("output", "ioc_gen.go", "name of generated file, relative to dir")
+	typeName := flag.String("type", "Graph", "name of generated graph type")
+	flag.Parse()
+
+	src, err := generate(*dir, *typeName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "iocgen:", err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(*dir, *output), src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "iocgen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+type Config struct {
+	DSN string
+}
+
+type UserRepository interface {
+	Name() string
+}
+
+type userRepository struct {
+	db *sql.DB
+}
+
+func (r *userRepository) Name() string {
+	return "user"
+}
+
+type UserService interface {
+	Name() string
+}
+
+type userService struct {
+	cfg        *Config `ioc:"service_cfg"`
+	repository UserRepository
+}
+
+func (s *userService) Name() string {
+	return s.repository.Name()
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
+
+func NewServiceConfig() *Config {
+	return &Config{DSN: "service"}
+}
+
+func NewDB(ctx context.Context, cfg *Config) (*sql.DB, error) {
+	return sql.Open("postgres", cfg.DSN)
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func NewUserService(cfg *Config, repository UserRepository) UserService {
+	return &userService{cfg: cfg, repository: repository}
+}
+
+var Providers = []ioc.Binding{
+	ioc.Singleton(NewConfig),
+	ioc.Singleton(NewServiceConfig, ioc.WithBindAlias("service_cfg")),
+	ioc.Singleton(NewDB),
+}
+
+func main() {
+	ioc.MustBindSingleton(NewUserRepository, ioc.WithBindMeta(&userRepository{}))
+	ioc.MustBindTransient(NewUserService, ioc.WithBindMeta(&userService{}))
+}
@@ -0,0 +1,23 @@
+package main
+
+import "github.com/josephsalimin/go-simple-ioc/ioc"
+
+type Config struct{}
+
+type Service struct {
+	cfg *Config `ioc:"other"`
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
+
+func NewService(cfg *Config) *Service {
+	return &Service{cfg: cfg}
+}
+
+func main() {
+	c := ioc.CreateContainer()
+	c.MustBindSingleton(NewConfig)
+	c.MustBindSingleton(NewService)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	ioctestImportPath = "github.com/josephsalimin/go-simple-ioc/ioctest"
+	generatedHeader   = "// Code generated by ioctestgen. DO NOT EDIT."
+)
+
+func parsePackage(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected 1 package in %v, but instead got %v", dir, len(pkgs))
+	}
+
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		names := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			files = append(files, pkg.Files[name])
+		}
+	}
+
+	return files, nil
+}
+
+// lookupInterfaces returns interfaces of given names from package, or all exported interfaces with methods if names
+// is empty, ordered by name.
+func lookupInterfaces(pkg *types.Package, names []string) ([]*types.TypeName, error) {
+	if len(names) == 0 {
+		for _, name := range pkg.Scope().Names() {
+			obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() {
+				continue
+			}
+			if iface, ok := obj.Type().Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var objs []*types.TypeName
+	for _, name := range names {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("can't find type %v in package %v", name, pkg.Name())
+		}
+		if _, ok := obj.Type().Underlying().(*types.Interface); !ok {
+			return nil, fmt.Errorf("expected %v to be interface, but instead got %v", name, obj.Type().Underlying())
+		}
+		objs = append(objs, obj)
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		return objs[i].Name() < objs[j].Name()
+	})
+
+	return objs, nil
+}
+
+type writer struct {
+	buf bytes.Buffer
+	pkg *types.Package
+	// imports is map of import path to package name used in the generated file.
+	imports map[string]string
+}
+
+func (w *writer) qualifier(pkg *types.Package) string {
+	if pkg == w.pkg {
+		return ""
+	}
+	w.imports[pkg.Path()] = pkg.Name()
+
+	return pkg.Name()
+}
+
+// writeStub writes stub struct of interface, its methods, and registration of the stub.
+func (w *writer) writeStub(obj *types.TypeName) error {
+	iface := obj.Type().Underlying().(*types.Interface)
+	stubName := obj.Name() + "Stub"
+
+	fmt.Fprintf(&w.buf, "// %v is stub of %v, each method calls func field of the same name with Func suffix.\n",
+		stubName, obj.Name())
+	fmt.Fprintf(&w.buf, "type %v struct {\n", stubName)
+	for idx := 0; idx < iface.NumMethods(); idx++ {
+		method := iface.Method(idx)
+		if !method.Exported() && method.Pkg() != w.pkg {
+			return fmt.Errorf("can't implement unexported method %v of %v", method.Name(), obj.Name())
+		}
+		sig := strings.TrimPrefix(types.TypeString(method.Type(), w.qualifier), "func")
+		fmt.Fprintf(&w.buf, "%vFunc func%v\n", method.Name(), sig)
+	}
+	w.buf.WriteString("}\n\n")
+
+	for idx := 0; idx < iface.NumMethods(); idx++ {
+		method := iface.Method(idx)
+		sig := method.Type().(*types.Signature)
+
+		params := make([]string, sig.Params().Len())
+		args := make([]string, sig.Params().Len())
+		for paramIdx := range params {
+			paramType := sig.Params().At(paramIdx).Type()
+			args[paramIdx] = fmt.Sprintf("p%d", paramIdx)
+			if sig.Variadic() && paramIdx == len(params)-1 {
+				paramType = paramType.(*types.Slice).Elem()
+				params[paramIdx] = fmt.Sprintf("p%d ...%v", paramIdx, types.TypeString(paramType, w.qualifier))
+				args[paramIdx] += "..."
+				continue
+			}
+			params[paramIdx] = fmt.Sprintf("p%d %v", paramIdx, types.TypeString(paramType, w.qualifier))
+		}
+
+		results := types.TypeString(sig.Results(), w.qualifier)
+		ret := "return "
+		if sig.Results().Len() == 0 {
+			results, ret = "", ""
+		}
+
+		fmt.Fprintf(&w.buf, "func (s *%v) %v(%v) %v {\n%vs.%vFunc(%v)\n}\n\n", stubName, method.Name(),
+			strings.Join(params, ", "), results, ret, method.Name(), strings.Join(args, ", "))
+	}
+
+	return nil
+}
+
+// generate type checks package in dir and returns source code of stubs of given interfaces.
+func generate(dir string, names []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	files, err := parsePackage(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check(files[0].Name.Name, fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := lookupInterfaces(pkg, names)
+	if err != nil {
+		return nil, err
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("can't find any interface in %v", dir)
+	}
+
+	w := &writer{pkg: pkg, imports: map[string]string{}}
+	for _, obj := range objs {
+		if err := w.writeStub(obj); err != nil {
+			return nil, err
+		}
+	}
+	w.buf.WriteString("func init() {\n")
+	for _, obj := range objs {
+		fmt.Fprintf(&w.buf, "ioctest.RegisterStub((*%v)(nil), new(%vStub))\n", obj.Name(), obj.Name())
+	}
+	w.buf.WriteString("}\n")
+
+	w.imports[ioctestImportPath] = "ioctest"
+	paths := make([]string, 0, len(w.imports))
+	for importPath := range w.imports {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v\n\npackage %v\n\nimport (\n", generatedHeader, pkg.Name())
+	for _, importPath := range paths {
+		if path.Base(importPath) == w.imports[importPath] {
+			fmt.Fprintf(&buf, "%q\n", importPath)
+		} else {
+			fmt.Fprintf(&buf, "%v %q\n", w.imports[importPath], importPath)
+		}
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(w.buf.Bytes())
+
+	return format.Source(buf.Bytes())
+}
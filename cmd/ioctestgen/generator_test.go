@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func testGenerateSource(t *testing.T, src string, names []string) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "ioctestgen")
+	if err != nil {
+		t.Fatalf("failed to create dir, err: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write source, err: %v", err)
+	}
+
+	return generate(dir, names)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generate stubs of exported interfaces", func(t *testing.T) {
+		dir := filepath.Join("testdata", "basic")
+		golden := filepath.Join(dir, "ioc_stub_test.go.golden")
+
+		src, err := generate(dir, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if *update {
+			if err := ioutil.WriteFile(golden, src, 0644); err != nil {
+				t.Fatalf("failed to update golden file, err: %v", err)
+			}
+		}
+
+		expected, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("failed to read golden file, err: %v", err)
+		}
+		assert.Equal(t, string(expected), string(src))
+	})
+
+	t.Run("generate stub of unknown type", func(t *testing.T) {
+		_, err := generate(filepath.Join("testdata", "basic"), []string{"Unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("generate stub of non interface type", func(t *testing.T) {
+		_, err := generate(filepath.Join("testdata", "basic"), []string{"User"})
+		assert.Error(t, err)
+	})
+
+	t.Run("generate stub of unexported interface", func(t *testing.T) {
+		src, err := testGenerateSource(t, `package main
+
+type store interface {
+	get(key string) string
+}
+`, []string{"store"})
+		assert.NoError(t, err)
+		assert.Contains(t, string(src), "func (s *storeStub) get(p0 string) string {")
+	})
+
+	t.Run("generate stub without interface", func(t *testing.T) {
+		_, err := testGenerateSource(t, "package main\n", nil)
+		assert.Error(t, err)
+	})
+}
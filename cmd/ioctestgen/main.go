@@ -0,0 +1,42 @@
+// Command ioctestgen generates stubs of interfaces for ioctest.WithAutoStub.
+//
+// Each stub is a struct with one func field per method, named after the method with Func suffix, and is registered
+// with ioctest.RegisterStub in init function of the generated file. Generated file is a test file by default, so
+// ioctest is not imported by non-test build.
+//
+// Usage with go generate:
+//
+//	//go:generate go run github.com/josephsalimin/go-simple-ioc/cmd/ioctestgen -type UserRepository,Cache
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package to analyse")
+	output := flag.String("output", "ioc_stub_test.go", "name of generated file, relative to dir")
+	typeNames := flag.String("type", "", "comma separated interface names, all exported interfaces if empty")
+	flag.Parse()
+
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	src, err := generate(*dir, names)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ioctestgen:", err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(*dir, *output), src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "ioctestgen:", err)
+		os.Exit(1)
+	}
+}
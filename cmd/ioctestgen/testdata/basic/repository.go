@@ -0,0 +1,22 @@
+package basic
+
+import (
+	"context"
+	"io"
+)
+
+type User struct {
+	ID string
+}
+
+type UserRepository interface {
+	Find(ctx context.Context, id string) (*User, error)
+	Save(ctx context.Context, users ...*User) error
+}
+
+type Cache interface {
+	io.Closer
+	Set(key string, value []byte)
+}
+
+type notInterface struct{}
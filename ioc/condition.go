@@ -0,0 +1,109 @@
+package ioc
+
+import "fmt"
+
+// bindCondition decides whether pending binding of label and alias is bound when container is finalized.
+// It is called without holding the container lock.
+type bindCondition func(c *container, label, alias string) (bool, error)
+
+// pendingBinding is conditional binding waiting for Finalize.
+type pendingBinding struct {
+	label       string
+	resolveFunc interface{}
+	opt         bindOption
+}
+
+// WithBindOnMissing binds only if type and alias of the binding is still not bound when container is finalized,
+// so library modules can provide default implementation that application can replace.
+func WithBindOnMissing() BindOption {
+	return func(opt *bindOption) {
+		opt.conditions = append(opt.conditions, func(c *container, label, alias string) (bool, error) {
+			return !c.isBound(label, alias), nil
+		})
+	}
+}
+
+// WithBindOnBound binds only if type of receiver is bound when container is finalized. Receiver is pointer to the
+// type, same as Resolve, and alias can be given using WithResolveAlias.
+func WithBindOnBound(receiver interface{}, opts ...ResolveOption) BindOption {
+	return func(opt *bindOption) {
+		opt.conditions = append(opt.conditions, func(c *container, _, _ string) (bool, error) {
+			receiverType, err := resolveTypePtrNonFunc(receiver)
+			if err != nil {
+				return false, err
+			}
+			o := &resolveOption{alias: c.root().option.defaultAlias}
+			applyResolveOption(o, opts)
+
+			return c.isBound(getLabel(receiverType), o.alias), nil
+		})
+	}
+}
+
+// WithBindOnCondition binds only if condition returns true when container is finalized.
+// Condition is called without holding the container lock, so it can resolve from the container.
+func WithBindOnCondition(condition func(c Container) bool) BindOption {
+	return func(opt *bindOption) {
+		opt.conditions = append(opt.conditions, func(c *container, _, _ string) (bool, error) {
+			return condition(c), nil
+		})
+	}
+}
+
+// isBound checks whether label and alias is bound in container or its parents.
+func (c *container) isBound(label, alias string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.getBinder(label, alias)
+	return err == nil
+}
+
+// evaluate checks whether all conditions of pending binding hold.
+func (p *pendingBinding) evaluate(c *container) (bool, error) {
+	for _, condition := range p.opt.conditions {
+		if ok, err := condition(c, p.label, p.opt.alias); err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Finalize evaluates conditions of conditional bindings in the order they are bound, and binds the ones whose
+// conditions hold. Binding bound by an earlier conditional binding is seen by conditions of the later ones.
+// Conditional bindings added after Finalize wait for the next Finalize. Run calls Finalize before validating.
+func (c *container) Finalize() error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	var errs multiError
+	for _, p := range pending {
+		ok, err := p.evaluate(c)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't evaluate condition of label %v with alias %v, err: %w", p.label,
+				p.opt.alias, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		opt := p.opt
+		opt.conditions = nil
+		c.mu.Lock()
+		err = c.bind(p.resolveFunc, &opt)
+		c.mu.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package ioc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Finalize(t *testing.T) {
+	t.Run("bind on missing", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindOnMissing())
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} })
+		cnt.MustBindSingleton(func() dTestInterface { return &dTestStruct{} }, WithBindOnMissing())
+		assert.NoError(t, cnt.Finalize())
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 2, s.intProp)
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+	})
+
+	t.Run("conditional binding is pending until finalize", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindOnMissing())
+
+		var s *testStruct
+		assert.True(t, errors.Is(cnt.Resolve(&s), ErrNotRegistered))
+		assert.NoError(t, cnt.Finalize())
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 1, s.intProp)
+	})
+
+	t.Run("bind on bound", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} },
+			WithBindOnBound((**testStruct)(nil)))
+		cnt.MustBindTransient(func() *dTestTagStruct { return &dTestTagStruct{} },
+			WithBindOnBound((**testStruct)(nil), WithResolveAlias("test")))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		assert.NoError(t, cnt.Finalize())
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		var tag *dTestTagStruct
+		assert.True(t, errors.Is(cnt.Resolve(&tag), ErrNotRegistered))
+	})
+
+	t.Run("later condition sees earlier conditional binding", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindOnMissing())
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindOnMissing())
+		assert.NoError(t, cnt.Finalize())
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 1, s.intProp)
+	})
+
+	t.Run("bind on condition", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} },
+			WithBindOnCondition(func(c Container) bool {
+				var s *testStruct
+				return c.Resolve(&s) == nil && s.intProp == 1
+			}))
+		cnt.MustBindTransient(func() *dTestTagStruct { return &dTestTagStruct{} },
+			WithBindOnCondition(func(Container) bool { return false }))
+		assert.NoError(t, cnt.Finalize())
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		var tag *dTestTagStruct
+		assert.True(t, errors.Is(cnt.Resolve(&tag), ErrNotRegistered))
+	})
+
+	t.Run("invalid receiver", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{} }, WithBindOnBound(testStruct{}))
+		assert.Error(t, cnt.Finalize())
+	})
+
+	t.Run("run finalizes conditional binding of module", func(t *testing.T) {
+		cnt := CreateContainer()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		started := false
+		cnt.MustInstall(&Module{
+			Name: "server",
+			Bindings: []Binding{Singleton(func(lc Lifecycle) *testStruct {
+				lc.Append(Hook{OnStart: func(context.Context) error {
+					started = true
+					cancel()
+					return nil
+				}})
+				return &testStruct{}
+			}, WithBindOnMissing())},
+		})
+
+		assert.Equal(t, 0, Run(ctx, cnt))
+		assert.True(t, started)
+	})
+}
@@ -0,0 +1,74 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// configAliasPrefix marks tag of meta field whose parameter is injected from configuration instead of binding,
+	// such as `ioc:"config:db.port,default=5432"`.
+	configAliasPrefix = "config:"
+	configDefaultOpt  = ",default="
+)
+
+// ErrConfigNotSet is returned when config key of parameter is not set and has no default.
+var ErrConfigNotSet = errors.New("config is not set")
+
+// ConfigProvider provides configuration values of parameters whose meta field is tagged with config key.
+// It is bound by iocconfig.BindConfig, or can be bound by hand.
+type ConfigProvider interface {
+	// LookupConfig returns value of key, ok is false if key is not set.
+	LookupConfig(key string) (value string, ok bool)
+	// ConvertConfig converts value into type t.
+	ConvertConfig(value string, t reflect.Type) (reflect.Value, error)
+}
+
+var configProviderType = reflect.TypeOf((*ConfigProvider)(nil)).Elem()
+
+// configKey is parsed config tag of a parameter.
+type configKey struct {
+	key        string
+	def        string
+	hasDefault bool
+}
+
+// isConfigDependency checks whether dependency is injected from configuration.
+func isConfigDependency(dependency [2]string) bool {
+	return strings.HasPrefix(dependency[1], configAliasPrefix)
+}
+
+// parseConfigKey parses alias of config dependency, default value is the rest of the tag so it may contain comma.
+func parseConfigKey(alias string) *configKey {
+	tag := strings.TrimPrefix(alias, configAliasPrefix)
+	if idx := strings.Index(tag, configDefaultOpt); idx >= 0 {
+		return &configKey{key: strings.TrimSpace(tag[:idx]), def: tag[idx+len(configDefaultOpt):], hasDefault: true}
+	}
+
+	return &configKey{key: strings.TrimSpace(strings.Split(tag, ",")[0])}
+}
+
+// value looks up key from provider, or uses its default, and converts it into t.
+func (k *configKey) value(provider ConfigProvider, t reflect.Type) (reflect.Value, error) {
+	raw, ok := provider.LookupConfig(k.key)
+	if !ok {
+		if !k.hasDefault {
+			return reflect.Value{}, fmt.Errorf("can't find config %v, err: %w", k.key, ErrConfigNotSet)
+		}
+		raw = k.def
+	}
+
+	v, err := provider.ConvertConfig(raw, t)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("can't convert config %v value %q into %v, err: %w", k.key, raw, t, err)
+	}
+
+	return v, nil
+}
+
+// findConfigProvider returns binder of ConfigProvider with default alias.
+func (c *container) findConfigProvider() (*binder, error) {
+	return c.findBinder(configProviderType, getLabel(configProviderType), c.root().option.defaultAlias)
+}
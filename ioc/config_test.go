@@ -0,0 +1,130 @@
+package ioc
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfigProvider map[string]string
+
+func (p testConfigProvider) LookupConfig(key string) (string, bool) {
+	value, ok := p[key]
+	return value, ok
+}
+
+func (p testConfigProvider) ConvertConfig(value string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(int64(d))
+	case t.Kind() == reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(int64(i))
+	default:
+		v.SetString(value)
+	}
+
+	return v, nil
+}
+
+type testConfigStruct struct {
+	host     string        `ioc:"config:db.host"`
+	port     int           `ioc:"config:db.port,default=5432"`
+	maxConns int           `ioc:"config:db.max_conns"`
+	timeout  time.Duration `ioc:"config:db.timeout,default=1s"`
+	s        *testStruct
+}
+
+type testConfigHostStruct struct {
+	host string `ioc:"config:db.host"`
+}
+
+func newTestConfigStruct(host string, port, maxConns int, timeout time.Duration, s *testStruct) *testConfigStruct {
+	return &testConfigStruct{host: host, port: port, maxConns: maxConns, timeout: timeout, s: s}
+}
+
+func TestContainer_Config(t *testing.T) {
+	bindTestConfig := func(provider testConfigProvider) Container {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() ConfigProvider { return provider })
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindTransient(newTestConfigStruct)
+		return cnt
+	}
+
+	t.Run("inject config by key", func(t *testing.T) {
+		cnt := bindTestConfig(testConfigProvider{"db.host": "localhost", "db.max_conns": "10", "db.timeout": "5s"})
+
+		var s *testConfigStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, "localhost", s.host)
+		assert.Equal(t, 5432, s.port)
+		assert.Equal(t, 10, s.maxConns)
+		assert.Equal(t, 5*time.Second, s.timeout)
+		assert.Equal(t, 1, s.s.intProp)
+	})
+
+	t.Run("config is not set", func(t *testing.T) {
+		cnt := bindTestConfig(testConfigProvider{"db.host": "localhost"})
+
+		var s *testConfigStruct
+		err := cnt.Resolve(&s)
+		assert.True(t, errors.Is(err, ErrConfigNotSet))
+		assert.Contains(t, err.Error(), "db.max_conns")
+	})
+
+	t.Run("unparsable config", func(t *testing.T) {
+		cnt := bindTestConfig(testConfigProvider{"db.host": "localhost", "db.max_conns": "ten"})
+
+		var s *testConfigStruct
+		err := cnt.Resolve(&s)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `can't convert config db.max_conns value "ten" into int`)
+	})
+
+	t.Run("config provider is not bound", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindTransient(newTestConfigStruct)
+
+		var s *testConfigStruct
+		assert.True(t, errors.Is(cnt.Resolve(&s), ErrNotRegistered))
+		assert.True(t, errors.Is(cnt.Validate(), ErrNotRegistered))
+	})
+
+	t.Run("override config provider resets dependents", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() ConfigProvider { return testConfigProvider{"db.host": "first"} })
+		cnt.MustBindSingleton(func(host string) *testConfigHostStruct { return &testConfigHostStruct{host: host} })
+
+		var s *testConfigHostStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, "first", s.host)
+
+		restore, err := cnt.Override(func() ConfigProvider { return testConfigProvider{"db.host": "second!"} })
+		assert.NoError(t, err)
+		defer restore()
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, "second!", s.host)
+	})
+}
+
+func TestParseConfigKey(t *testing.T) {
+	assert.Equal(t, &configKey{key: "db.host"}, parseConfigKey("config:db.host"))
+	assert.Equal(t, &configKey{key: "db.port", def: "5432", hasDefault: true},
+		parseConfigKey("config:db.port,default=5432"))
+	assert.Equal(t, &configKey{key: "hosts", def: "a,b", hasDefault: true}, parseConfigKey("config:hosts,default=a,b"))
+	assert.Equal(t, &configKey{key: "db.host", def: "", hasDefault: true}, parseConfigKey("config:db.host,default="))
+}
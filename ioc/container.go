@@ -1,19 +1,36 @@
 package ioc
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 const structTagKey = "ioc"
 const defaultAlias = "default"
 
+var (
+	contextLabel = getLabel(reflect.TypeOf((*context.Context)(nil)).Elem())
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
 var (
 	ErrNotRegistered             = errors.New("information is not registered to container")
 	ErrAliasNotKnown             = errors.New("alias is not known")
 	ErrInstanceMustNotBeFunction = errors.New("instance must not be a function")
+	ErrNotExported               = errors.New("information is private to its module")
+	ErrNotInScope                = errors.New("scoped information must be resolved from a scope")
+	ErrAlreadyBound              = errors.New("information is already bound")
+	ErrAmbiguousDependency       = errors.New("dependency can only be matched by position")
 )
 
 // Container provides utility functions to bind and resolve.
@@ -23,13 +40,41 @@ type Container interface {
 	MustBindSingleton(interface{}, ...BindOption)
 	BindTransient(interface{}, ...BindOption) error
 	MustBindTransient(interface{}, ...BindOption)
+	BindScoped(interface{}, ...BindOption) error
+	MustBindScoped(interface{}, ...BindOption)
 	Resolve(interface{}, ...ResolveOption) error
+	ResolveContext(context.Context, interface{}, ...ResolveOption) error
 	MustResolve(interface{}, ...ResolveOption)
+	Call(interface{}) ([]interface{}, error)
+	CallContext(context.Context, interface{}) ([]interface{}, error)
+	Install(...*Module) error
+	MustInstall(...*Module)
+	Start(context.Context) error
+	Stop(context.Context) error
+	Validate() error
+	CreateScope() Scope
+	Override(interface{}, ...BindOption) (func(), error)
+	Clone(...ContainerOption) Container
+	Snapshot() *SnapshotToken
+	Restore(*SnapshotToken, ...RestoreOption) error
+	Finalize() error
 }
 
+type lifetime int
+
+const (
+	lifetimeTransient lifetime = iota
+	lifetimeSingleton
+	lifetimeScoped
+)
+
 type binder struct {
-	// isSingleton is flag to check whether it is singleton or transient.
-	isSingleton bool
+	// lifetime is flag to check whether it is singleton, transient, or scoped.
+	lifetime lifetime
+	// owner is container where the binder is bound, singleton dependencies are resolved from it.
+	owner *container
+	// alias is alias of the binder in container.
+	alias string
 	// resolveFunc is internal function that resolves the actual implementation.
 	resolveFunc interface{}
 	// meta is metadata information of the instance.
@@ -38,21 +83,183 @@ type binder struct {
 	instance interface{}
 	// dependencies is a list of dependency from the implementation.
 	dependencies [][2]string
+	// module is name of the module that installs the binder, empty if bound directly.
+	module string
+	// isPrivate is flag to check whether only binders from the same module can depend on it.
+	isPrivate bool
+	// profiles is list of profiles where the binder is active, empty if it is always active.
+	profiles []string
+	// isPrimary is flag to choose the binder when default alias of its label is not bound.
+	isPrimary bool
 }
 
 type binderMap map[string]*binder
 
+// multiError is list of errors that happens together, matches any of them using errors.Is.
+type multiError []error
+
+func (e multiError) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e multiError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Implementation of Container interface.
 type container struct {
 	// Map of string to map of string interface.
 	// First key is the type (can be interface or struct) while second key is alias (default is default key)
 	// to the implementation.
 	cnt map[string]binderMap
+	// Map of installed module name to its install state.
+	modules map[string]moduleState
+	// lifecycle holds hooks registered by bindings.
+	lifecycle *lifecycle
+	// parent is container where the scope is created from, nil for root container.
+	parent *container
+	// mu is shared by container and all of its scopes, as they share singleton instances. It guards bindings and
+	// saved instances, and is not held while resolve functions are called.
+	mu *sync.Mutex
+	// scoped is map of scoped binder to the instance created in the scope.
+	scoped map[*binder]interface{}
+	// constructions is map of singleton binder owned by the container, or scoped binder of the scope, to its
+	// construction in progress.
+	constructions map[*binder]*construction
+	// waiting is map of goroutine to construction it waits for, only kept by root container.
+	waiting map[uint64]*construction
+	// disposables is list of scoped instances that will be closed when the scope is disposed.
+	disposables []io.Closer
+	// isDisposed is flag to check whether scope is already disposed.
+	isDisposed bool
+	// generation increases whenever binders of the container change, to invalidate compiled plans.
+	generation uint64
+	// plans is map of binder to its compiled plan for the container.
+	plans map[*binder]*plan
+	// option is option of root container, empty for scope.
+	option containerOption
+	// profiles is map of profile to binders bound with it, including inactive ones which are not in cnt.
+	profiles map[string]map[string]binderMap
+	// pending is list of conditional bindings waiting for Finalize, ordered by bind.
+	pending []*pendingBinding
+}
+
+// DuplicateBindPolicy decides what happens when binding type and alias that is already bound.
+type DuplicateBindPolicy int
+
+const (
+	// DuplicateBindOverwrite replaces existing binding, it is the default policy.
+	DuplicateBindOverwrite DuplicateBindPolicy = iota
+	// DuplicateBindReject returns ErrAlreadyBound and keeps existing binding.
+	DuplicateBindReject
+)
+
+type containerOption struct {
+	tagKey         string
+	defaultAlias   string
+	isStrict       bool
+	duplicateBind  DuplicateBindPolicy
+	missingHandler MissingHandler
+	observers      []Observer
+	metrics        Metrics
+	logger         activityLogger
+	profiles       []string
+}
+
+type ContainerOption func(o *containerOption)
+
+// WithTagKey sets struct tag key used to find alias of dependencies from fields of meta, default is ioc.
+func WithTagKey(key string) ContainerOption {
+	return func(o *containerOption) {
+		o.tagKey = key
+	}
+}
+
+// WithDefaultAlias sets alias used when alias is not given on bind, resolve, and struct tag, default is default.
+func WithDefaultAlias(alias string) ContainerOption {
+	return func(o *containerOption) {
+		o.defaultAlias = alias
+	}
+}
+
+// WithStrict makes bind fail with ErrAmbiguousDependency if resolve function has more than one parameter of the
+// same type, or meta has more than one field of a parameter type, as they can only be matched by position.
+func WithStrict() ContainerOption {
+	return func(o *containerOption) {
+		o.isStrict = true
+	}
+}
+
+// WithDuplicateBind sets policy when binding type and alias that is already bound.
+func WithDuplicateBind(policy DuplicateBindPolicy) ContainerOption {
+	return func(o *containerOption) {
+		o.duplicateBind = policy
+	}
+}
+
+func applyContainerOption(o *containerOption, opts []ContainerOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
 }
 
 // CreateContainer creates new struct that implements Container interface.
-func CreateContainer() Container {
-	return &container{cnt: map[string]binderMap{}}
+func CreateContainer(opts ...ContainerOption) Container {
+	c := &container{mu: &sync.Mutex{}, option: containerOption{tagKey: structTagKey, defaultAlias: defaultAlias}}
+	applyContainerOption(&c.option, opts)
+	c.clear()
+
+	return c
+}
+
+// root returns root container of scope, or the container itself if it is not a scope.
+func (c *container) root() *container {
+	for c.parent != nil {
+		c = c.parent
+	}
+
+	return c
+}
+
+// walkBinders calls fn for every binder in container, ordered by label and alias.
+func (c *container) walkBinders(fn func(label, alias string, b *binder) error) error {
+	return walkBinderMap(c.cnt, fn)
+}
+
+// walkBinderMap calls fn for every binder in cnt, ordered by label and alias.
+func walkBinderMap(cnt map[string]binderMap, fn func(label, alias string, b *binder) error) error {
+	labels := make([]string, 0, len(cnt))
+	for label := range cnt {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		aliases := make([]string, 0, len(cnt[label]))
+		for alias := range cnt[label] {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+
+		for _, alias := range aliases {
+			if err := fn(label, alias, cnt[label][alias]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 func getLabel(p reflect.Type) string {
@@ -74,16 +281,40 @@ func resolveTypePtrNonFunc(instance interface{}) (reflect.Type, error) {
 	return instanceType, nil
 }
 
+func (c *container) clear() {
+	alias := c.root().option.defaultAlias
+	c.lifecycle = &lifecycle{}
+	c.cnt = map[string]binderMap{
+		lifecycleLabel: {alias: {lifetime: lifetimeSingleton, owner: c, alias: alias,
+			instance: c.lifecycle, resolveFunc: func() Lifecycle { return c.lifecycle }}},
+	}
+	c.profiles = nil
+	c.pending = nil
+	c.modules = map[string]moduleState{}
+	c.scoped = map[*binder]interface{}{}
+	c.plans = map[*binder]*plan{}
+	c.generation++
+}
+
 // Clear clears root / default container internal data.
-// Does not handles multiple threads.
 func (c *container) Clear() {
-	c.cnt = map[string]binderMap{}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clear()
 }
 
 type bindOption struct {
-	alias       string
-	meta        interface{}
-	isSingleton bool
+	alias     string
+	meta      interface{}
+	lifetime  lifetime
+	module    string
+	isPrivate bool
+	// isOverride is flag to replace existing binder regardless of duplicate bind policy.
+	isOverride bool
+	profiles   []string
+	conditions []bindCondition
+	isPrimary  bool
 }
 
 type BindOption func(o *bindOption)
@@ -102,13 +333,17 @@ func WithBindMeta(meta interface{}) BindOption {
 
 type resolveOption struct {
 	alias string
+	// fallbacks is list of aliases tried in order when alias is not bound.
+	fallbacks []string
 }
 
 type ResolveOption func(o *resolveOption)
 
-func WithResolveAlias(alias string) ResolveOption {
+// WithResolveAlias sets alias to resolve, followed by fallback aliases that are tried in order if it is not bound.
+func WithResolveAlias(alias string, fallbacks ...string) ResolveOption {
 	return func(o *resolveOption) {
 		o.alias = alias
+		o.fallbacks = fallbacks
 	}
 }
 
@@ -118,22 +353,37 @@ func applyBindOption(o *bindOption, opts []BindOption) {
 	}
 }
 
-func getDependencies(resolveFuncType reflect.Type, instanceType reflect.Type) [][2]string {
+func getDependencies(resolveFuncType reflect.Type, instanceType reflect.Type, option *containerOption) (
+	[][2]string, error) {
 	labelMap := map[string][]int{}
 	labelCtrMap := make(map[string]int)
 	for idx := 0; idx < resolveFuncType.NumIn(); idx++ {
 		paramType := resolveFuncType.In(idx)
 		label := getLabel(paramType)
+		// Context parameter is filled with resolve context instead of bound dependency.
+		if label == contextLabel {
+			continue
+		}
 		if _, ok := labelMap[label]; !ok {
 			labelMap[label] = []int{idx}
 			labelCtrMap[label] = 0
 		} else {
+			if option.isStrict {
+				return nil, fmt.Errorf("can't match more than one parameter of label %v, err: %w", label,
+					ErrAmbiguousDependency)
+			}
 			labelMap[label] = append(labelMap[label], idx)
 		}
 	}
 
 	dependencies := make([][2]string, resolveFuncType.NumIn())
-	if instanceType.Kind() != reflect.Interface {
+	for idx := range dependencies {
+		if getLabel(resolveFuncType.In(idx)) == contextLabel {
+			dependencies[idx] = [2]string{contextLabel, ""}
+		}
+	}
+	// Instance type is nil when function is called directly, so there is no tag to look for.
+	if instanceType != nil && instanceType.Kind() == reflect.Struct {
 		for idx := 0; idx < instanceType.NumField(); idx++ {
 			field := instanceType.Field(idx)
 			label := getLabel(field.Type)
@@ -141,15 +391,28 @@ func getDependencies(resolveFuncType reflect.Type, instanceType reflect.Type) []
 			if !ok {
 				continue
 			}
+			// Fields are matched to parameters of the same type by their position, extra fields are ignored.
+			if labelCtrMap[label] >= len(inIdxList) {
+				if option.isStrict {
+					return nil, fmt.Errorf("can't match more than one field of label %v, err: %w", label,
+						ErrAmbiguousDependency)
+				}
+				continue
+			}
 			inIdx := inIdxList[labelCtrMap[label]]
 			labelCtrMap[label]++
 
-			tag, ok := field.Tag.Lookup(structTagKey)
+			tag, ok := field.Tag.Lookup(option.tagKey)
+			// Config tag is kept whole as alias, since its default value may contain comma.
+			if strings.HasPrefix(tag, configAliasPrefix) {
+				dependencies[inIdx] = [2]string{label, tag}
+				continue
+			}
 			v := strings.Split(tag, ",")
 
 			alias := v[0]
 			if alias == "" {
-				alias = defaultAlias
+				alias = option.defaultAlias
 			}
 
 			dependencies[inIdx] = [2]string{label, alias}
@@ -159,11 +422,11 @@ func getDependencies(resolveFuncType reflect.Type, instanceType reflect.Type) []
 	// Leftover will be set to default
 	for label, inIdxList := range labelMap {
 		for i := labelCtrMap[label]; i < len(inIdxList); i++ {
-			dependencies[inIdxList[i]] = [2]string{label, defaultAlias}
+			dependencies[inIdxList[i]] = [2]string{label, option.defaultAlias}
 		}
 	}
 
-	return dependencies
+	return dependencies, nil
 }
 
 func (c *container) bind(resolveFunc interface{}, opt *bindOption) error {
@@ -175,6 +438,9 @@ func (c *container) bind(resolveFunc interface{}, opt *bindOption) error {
 	if resolveFuncType.NumOut() < 1 {
 		return fmt.Errorf("expected minimum output of 1, but instead got: %v", resolveFuncType.NumOut())
 	}
+	if resolveFuncType.NumOut() > 2 || (resolveFuncType.NumOut() == 2 && resolveFuncType.Out(1) != errorType) {
+		return fmt.Errorf("expected second output to be error, but instead got: %v", resolveFuncType)
+	}
 
 	instanceType := resolveFuncType.Out(0)
 	if instanceType.Kind() != reflect.Ptr && instanceType.Kind() != reflect.Interface {
@@ -198,14 +464,48 @@ func (c *container) bind(resolveFunc interface{}, opt *bindOption) error {
 		instanceType = metaType.Elem()
 	}
 
-	dependencies := getDependencies(resolveFuncType, instanceType)
-	if v, ok := c.cnt[label]; !ok {
-		c.cnt[label] = binderMap{
-			opt.alias: {isSingleton: opt.isSingleton, resolveFunc: resolveFunc, meta: opt.meta, dependencies: dependencies},
+	option := &c.root().option
+	dependencies, err := getDependencies(resolveFuncType, instanceType, option)
+	if err != nil {
+		return err
+	}
+	// Conditional binding is bound by Finalize, after the dependencies are checked.
+	if len(opt.conditions) > 0 {
+		c.pending = append(c.pending, &pendingBinding{label: label, resolveFunc: resolveFunc, opt: *opt})
+		return nil
+	}
+	if !c.isProfileActive(opt.profiles) {
+		c.bindProfiles(label, &binder{lifetime: opt.lifetime, owner: c, alias: opt.alias, resolveFunc: resolveFunc,
+			meta: opt.meta, dependencies: dependencies, module: opt.module, isPrivate: opt.isPrivate,
+			profiles: opt.profiles, isPrimary: opt.isPrimary})
+		return nil
+	}
+	b := &binder{lifetime: opt.lifetime, owner: c, alias: opt.alias, resolveFunc: resolveFunc, meta: opt.meta,
+		dependencies: dependencies, module: opt.module, isPrivate: opt.isPrivate, profiles: opt.profiles,
+		isPrimary: opt.isPrimary}
+	existing, overwritten := c.cnt[label][opt.alias]
+	// Binding of active profile takes precedence over binding without profile whatever the bind order, and the one
+	// without profile is kept aside for graph of other profiles.
+	if overwritten && !opt.isOverride && (len(existing.profiles) == 0) != (len(opt.profiles) == 0) {
+		if len(opt.profiles) == 0 {
+			return c.bindFallback(label, b, option.duplicateBind)
 		}
+		c.bindProfile(noProfile, label, existing)
+		overwritten = false
+	}
+	if overwritten && !opt.isOverride && option.duplicateBind == DuplicateBindReject {
+		return fmt.Errorf("can't bind label %v with alias %v, err: %w", label, opt.alias, ErrAlreadyBound)
+	}
+	c.generation++
+	if option.logger != nil {
+		option.logger.logBind(label, opt.alias, opt.lifetime, resolveFunc, overwritten)
+	}
+	if v, ok := c.cnt[label]; !ok {
+		c.cnt[label] = binderMap{opt.alias: b}
 	} else {
-		v[opt.alias] = &binder{isSingleton: opt.isSingleton, resolveFunc: resolveFunc, meta: opt.meta, dependencies: dependencies}
+		v[opt.alias] = b
 	}
+	c.bindProfiles(label, b)
 
 	return nil
 }
@@ -214,11 +514,15 @@ func (c *container) bind(resolveFunc interface{}, opt *bindOption) error {
 // As it is singleton, after first resolve, container will save resolved information and immediately returns data
 // for next resolve.
 // First parameter must be a function that returns interface or pointer struct and meta can be nil or must implements
-// returned interface type from resolveFunc.
+// returned interface type from resolveFunc. The function may also returns error as second output, and may accept
+// context.Context parameter that will be filled with resolve context.
 func (c *container) BindSingleton(resolveFunc interface{}, opts ...BindOption) error {
-	o := &bindOption{alias: defaultAlias, isSingleton: true}
+	o := &bindOption{alias: c.root().option.defaultAlias, lifetime: lifetimeSingleton}
 	applyBindOption(o, opts)
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.bind(resolveFunc, o)
 }
 
@@ -232,11 +536,15 @@ func (c *container) MustBindSingleton(resolveFunc interface{}, opts ...BindOptio
 // BindTransient binds given resolveFunc function and metadata information to container without singleton flag.
 // Each resolve will create new object.
 // First parameter must be a function that returns interface or pointer struct and meta can be nil or must implements
-// returned interface type from resolveFunc.
+// returned interface type from resolveFunc. The function may also returns error as second output, and may accept
+// context.Context parameter that will be filled with resolve context.
 func (c *container) BindTransient(resolveFunc interface{}, opts ...BindOption) error {
-	o := &bindOption{alias: defaultAlias, isSingleton: false}
+	o := &bindOption{alias: c.root().option.defaultAlias, lifetime: lifetimeTransient}
 	applyBindOption(o, opts)
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.bind(resolveFunc, o)
 }
 
@@ -247,14 +555,40 @@ func (c *container) MustBindTransient(resolveFunc interface{}, opts ...BindOptio
 	}
 }
 
-func (c *container) getBinder(label, binderLabel string) (*binder, error) {
+// getBinder finds binder from container, and falls back to parent container if it is a scope.
+// Default alias that is not bound falls back to the binder marked as primary.
+func (c *container) getBinder(label, alias string) (*binder, error) {
+	b, err := c.getAliasBinder(label, alias)
+	if err == nil || alias != c.root().option.defaultAlias {
+		return b, err
+	}
+
+	primary, primaryErr := c.getPrimaryBinder(label)
+	if primaryErr != nil {
+		return nil, primaryErr
+	}
+	if primary == nil {
+		return nil, err
+	}
+
+	return primary, nil
+}
+
+// getAliasBinder finds binder of exact alias from container, and falls back to parent container if it is a scope.
+func (c *container) getAliasBinder(label, binderLabel string) (*binder, error) {
 	binderMap, ok := c.cnt[label]
 	if !ok {
+		if c.parent != nil {
+			return c.parent.getAliasBinder(label, binderLabel)
+		}
 		return nil, fmt.Errorf("can't find dependencies from label %v, err: %w", label, ErrNotRegistered)
 	}
 
 	binder, ok := binderMap[binderLabel]
 	if !ok {
+		if c.parent != nil {
+			return c.parent.getAliasBinder(label, binderLabel)
+		}
 		return nil, fmt.Errorf("can't find dependencies from label %v with alias %v, err: %w",
 			label, binderLabel, ErrAliasNotKnown)
 	}
@@ -262,81 +596,343 @@ func (c *container) getBinder(label, binderLabel string) (*binder, error) {
 	return binder, nil
 }
 
+// checkExported makes sure private binder is only used by binders from the same module.
+// from is nil when binder is resolved directly from container.
+func checkExported(b *binder, from *binder, label, alias string) error {
+	if !b.isPrivate || (from != nil && from.module == b.module) {
+		return nil
+	}
+
+	return fmt.Errorf("can't use dependencies from label %v with alias %v of module %v, err: %w",
+		label, alias, b.module, ErrNotExported)
+}
+
 func applyResolveOption(o *resolveOption, opts []ResolveOption) {
 	for _, opt := range opts {
 		opt(o)
 	}
 }
 
-func (c *container) buildDependencyArguments(b *binder) ([]reflect.Value, error) {
-	resolveFuncType := reflect.TypeOf(b.resolveFunc)
-	in := make([]reflect.Value, 0)
-	for idx := 0; idx < resolveFuncType.NumIn(); idx++ {
-		dependency := b.dependencies[idx]
-		argBinder, err := c.getBinder(dependency[0], dependency[1])
-		if err != nil {
-			return nil, err
+// isContextDependency checks whether dependency is filled with resolve context.
+func isContextDependency(dependency [2]string) bool {
+	return dependency[0] == contextLabel && dependency[1] == ""
+}
+
+// construction is singleton or scoped instance being constructed, so concurrent resolves of the same binder wait for
+// it instead of calling the resolve function again.
+type construction struct {
+	// goroutine is id of goroutine that calls the resolve function.
+	goroutine uint64
+	done      chan struct{}
+	instance  interface{}
+	err       error
+}
+
+// wait waits until construction is done or ctx is done, so resolve with deadline doesn't wait for slow resolve
+// function called by other resolve.
+func (con *construction) wait(ctx context.Context, b *binder) (interface{}, bool, error) {
+	select {
+	case <-con.done:
+		return con.instance, con.err == nil, con.err
+	case <-ctx.Done():
+		return nil, false, fmt.Errorf("can't wait for resolve function %v, err: %w", reflect.TypeOf(b.resolveFunc),
+			ctx.Err())
+	}
+}
+
+// isBlockedBy checks whether construction is done by goroutine, directly or by other goroutine waiting for it, so
+// goroutine would wait for itself when the dependencies are circular, including resolve called by resolve function.
+// Must be called while holding the lock.
+func (c *container) isBlockedBy(con *construction, goroutine uint64) bool {
+	waiting := c.root().waiting
+	for steps := 0; con != nil && steps <= len(waiting); steps++ {
+		if con.goroutine == goroutine {
+			return true
 		}
+		con = waiting[con.goroutine]
+	}
 
-		res, err := c.invoke(argBinder)
-		if err != nil {
-			return nil, err
+	return false
+}
+
+// waitConstruction waits for construction of other goroutine, and records it as waited by goroutine meanwhile.
+func (c *container) waitConstruction(ctx context.Context, b *binder, con *construction,
+	goroutine uint64) (interface{}, bool, error) {
+	root := c.root()
+	if root.waiting == nil {
+		root.waiting = map[uint64]*construction{}
+	}
+	root.waiting[goroutine] = con
+	c.mu.Unlock()
+	instance, cached, err := con.wait(ctx, b)
+	c.mu.Lock()
+	delete(root.waiting, goroutine)
+	c.mu.Unlock()
+
+	return instance, cached, err
+}
+
+// goroutineID returns id of current goroutine, parsed from header of its stack trace, e.g. "goroutine 7 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[len("goroutine "):runtime.Stack(buf[:], false)]
+	id, _ := strconv.ParseUint(string(header[:bytes.IndexByte(header, ' ')]), 10, 64)
+
+	return id
+}
+
+// invoke returns instance of binder, and notifies observers and metrics of root container around it.
+func (c *container) invoke(ctx context.Context, b *binder) (interface{}, error) {
+	option := &c.root().option
+	if len(option.observers) == 0 && option.metrics == nil && option.logger == nil {
+		instance, _, err := c.instantiate(ctx, b)
+		return instance, err
+	}
+
+	instanceType := reflect.TypeOf(b.resolveFunc).Out(0)
+	for _, observer := range option.observers {
+		ctx = observer.OnResolveStart(ctx, instanceType, b.alias)
+	}
+	start := time.Now()
+	instance, cached, err := c.instantiate(ctx, b)
+	duration := time.Since(start)
+	for _, observer := range option.observers {
+		observer.OnResolveEnd(ctx, instanceType, b.alias, duration, cached, err)
+	}
+	if option.metrics != nil {
+		label := getLabel(instanceType)
+		option.metrics.IncResolve(label, b.alias, b.lifetime.String(), cached)
+		if !cached && err == nil {
+			option.metrics.ObserveConstruct(label, b.alias, b.lifetime.String(), duration)
+		}
+	}
+	if option.logger != nil {
+		option.logger.logResolve(getLabel(instanceType), b.alias, b.lifetime, duration, cached, err)
+	}
+
+	return instance, err
+}
+
+// instantiate returns instance of binder, cached is true if saved singleton or scoped instance, or instance
+// constructed by other resolve, is returned without calling the resolve function.
+// The lock is only held to read and save instances, so resolve function may use the container. Singleton and scoped
+// instance are constructed once, and other resolves of them wait until the construction or their ctx is done.
+func (c *container) instantiate(ctx context.Context, b *binder) (interface{}, bool, error) {
+	c.mu.Lock()
+	switch b.lifetime {
+	case lifetimeSingleton:
+		if instance := b.instance; instance != nil {
+			c.mu.Unlock()
+			return instance, true, nil
+		}
+		// Singleton is resolved from container that owns it, so it never captures information from a scope.
+		c = b.owner
+	case lifetimeScoped:
+		if c.parent == nil {
+			c.mu.Unlock()
+			return nil, false, fmt.Errorf("can't call resolve function %v, err: %w", reflect.TypeOf(b.resolveFunc),
+				ErrNotInScope)
 		}
+		if instance, ok := c.scoped[b]; ok {
+			c.mu.Unlock()
+			return instance, true, nil
+		}
+	}
 
-		in = append(in, reflect.ValueOf(res))
+	var con *construction
+	if b.lifetime != lifetimeTransient {
+		goroutine := goroutineID()
+		if con = c.constructions[b]; con != nil {
+			if c.isBlockedBy(con, goroutine) {
+				c.mu.Unlock()
+				return nil, false, fmt.Errorf("can't call resolve function %v, err: %w",
+					reflect.TypeOf(b.resolveFunc), ErrCircularDependency)
+			}
+			return c.waitConstruction(ctx, b, con, goroutine)
+		}
+		con = &construction{goroutine: goroutine, done: make(chan struct{})}
+		if c.constructions == nil {
+			c.constructions = map[*binder]*construction{}
+		}
+		c.constructions[b] = con
+	}
+	p, err := c.getPlan(b)
+	c.mu.Unlock()
+
+	var instance interface{}
+	if err == nil {
+		instance, err = c.construct(ctx, b, p)
+	}
+	if con == nil {
+		return instance, false, err
 	}
 
-	return in, nil
+	c.mu.Lock()
+	delete(c.constructions, b)
+	if err == nil {
+		instance, err = c.save(b, instance)
+	}
+	c.mu.Unlock()
+	con.instance, con.err = instance, err
+	close(con.done)
+
+	return instance, false, err
 }
 
-func (c *container) invoke(b *binder) (interface{}, error) {
-	if b.instance != nil {
-		return b.instance, nil
+// construct resolves dependencies of binder and calls its resolve function without holding the lock.
+func (c *container) construct(ctx context.Context, b *binder, p *plan) (interface{}, error) {
+	args := p.acquireArguments()
+	err := c.buildDependencyArguments(ctx, b, p, *args)
+	if err == nil {
+		err = ctx.Err()
+		if err != nil {
+			err = fmt.Errorf("can't call resolve function %v, err: %w", reflect.TypeOf(b.resolveFunc), err)
+		}
+	}
+	if err != nil {
+		p.releaseArguments(args)
+		return nil, err
 	}
 
-	args, err := c.buildDependencyArguments(b)
+	// Panicking singleton is not saved, so it can be resolved again.
+	results, err := p.call(*args)
+	p.releaseArguments(args)
 	if err != nil {
 		return nil, err
 	}
+	if len(results) > 1 && !results[1].IsNil() {
+		return nil, fmt.Errorf("failed to call resolve function %v, err: %w", reflect.TypeOf(b.resolveFunc),
+			results[1].Interface().(error))
+	}
 
-	resolveFuncValue := reflect.ValueOf(b.resolveFunc)
-	results := resolveFuncValue.Call(args)
+	return results[0].Interface(), nil
+}
 
-	if b.isSingleton {
-		b.instance = results[0].Interface()
+// save saves constructed singleton or scoped instance of binder, must be called while holding the lock.
+// Scoped instance constructed after the scope is disposed is closed instead.
+func (c *container) save(b *binder, instance interface{}) (interface{}, error) {
+	switch b.lifetime {
+	case lifetimeSingleton:
+		b.instance = instance
+		c.lifecycle.appendInstance(instance)
+	case lifetimeScoped:
+		closer, isCloser := instance.(io.Closer)
+		if c.isDisposed {
+			if isCloser {
+				_ = closer.Close()
+			}
+			return nil, ErrScopeDisposed
+		}
+		c.scoped[b] = instance
+		if isCloser {
+			c.disposables = append(c.disposables, closer)
+		}
 	}
 
-	return results[0].Interface(), nil
+	return instance, nil
 }
 
-func (c *container) resolve(receiver interface{}, label string, opt *resolveOption) (err error) {
+func (c *container) resolve(ctx context.Context, receiver interface{}, label string, opt *resolveOption) (err error) {
 	receiverType, err := resolveTypePtrNonFunc(receiver)
 	if err != nil {
 		return err
 	}
 
+	c.mu.Lock()
+	if c.isDisposed {
+		c.mu.Unlock()
+		return ErrScopeDisposed
+	}
+
 	if label == "" {
 		label = getLabel(receiverType)
 	}
-	b, err := c.getBinder(label, opt.alias)
+	b, alias, err := c.findAliasBinder(receiverType, label, opt)
+	if err == nil {
+		err = checkExported(b, nil, label, alias)
+	}
+	c.mu.Unlock()
 	if err != nil {
-		return err
+		return withFrame(err, newResolveFrame(label, alias, b))
 	}
 
 	receiverValue := reflect.ValueOf(receiver).Elem()
-	result, err := c.invoke(b)
+	result, err := c.invoke(ctx, b)
+	if err != nil {
+		return withFrame(err, newResolveFrame(label, alias, b))
+	}
 	receiverValue.Set(reflect.ValueOf(result))
 
 	return nil
 }
 
 // Resolve resolves given receiver to appropriate bound information in container.
-// Will returns ErrNotRegistered, ErrAliasNotKnown, or any relevant errors if failed to resolve.
+// Will returns *ResolveError wrapping ErrNotRegistered, ErrAliasNotKnown, ErrNotExported, or any relevant errors
+// if failed to resolve.
 func (c *container) Resolve(receiver interface{}, opts ...ResolveOption) (err error) {
-	o := &resolveOption{alias: defaultAlias}
+	o := &resolveOption{alias: c.root().option.defaultAlias}
+	applyResolveOption(o, opts)
+
+	return c.resolve(context.Background(), receiver, "", o)
+}
+
+// ResolveContext is same as Resolve, but given ctx is passed to every resolve function that has context.Context
+// parameter, and resolving will be aborted with wrapped context error once ctx is done, including while waiting for
+// singleton or scoped instance constructed by other resolve.
+func (c *container) ResolveContext(ctx context.Context, receiver interface{}, opts ...ResolveOption) error {
+	o := &resolveOption{alias: c.root().option.defaultAlias}
 	applyResolveOption(o, opts)
 
-	return c.resolve(receiver, "", o)
+	return c.resolve(ctx, receiver, "", o)
+}
+
+func (c *container) call(ctx context.Context, fn interface{}) ([]interface{}, error) {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("expected function, but instead got %v", fnType)
+	}
+
+	c.mu.Lock()
+	if c.isDisposed {
+		c.mu.Unlock()
+		return nil, ErrScopeDisposed
+	}
+	dependencies, err := getDependencies(fnType, nil, &c.root().option)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	b := &binder{resolveFunc: fn, dependencies: dependencies}
+	// Plan of the function is not cached, as the function is not bound to container.
+	p, err := c.compilePlan(b)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	in := make([]reflect.Value, len(dependencies))
+	if err := c.buildDependencyArguments(ctx, b, p, in); err != nil {
+		return nil, err
+	}
+
+	results := p.fn.Call(in)
+	outputs := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		outputs = append(outputs, result.Interface())
+	}
+
+	return outputs, nil
+}
+
+// Call resolves all parameters of given function from container with default alias, then calls it outside
+// container lock, so the function may use the container.
+// Returns outputs of the function.
+func (c *container) Call(fn interface{}) ([]interface{}, error) {
+	return c.call(context.Background(), fn)
+}
+
+// CallContext is same as Call, but given ctx is used to resolve parameters, including context.Context parameter.
+func (c *container) CallContext(ctx context.Context, fn interface{}) ([]interface{}, error) {
+	return c.call(ctx, fn)
 }
 
 // Resolve resolves given receiver to appropriate bound information in container.
@@ -1,9 +1,15 @@
 package ioc
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type testStruct struct {
@@ -315,3 +321,418 @@ func TestContainer_MustBindTransient(t *testing.T) {
 		}
 	})
 }
+
+func TestContainer_ResolveContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("resolve function receives resolve context", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindTransient(func(ctx context.Context) *testStruct {
+			return &testStruct{intProp: ctx.Value(ctxKey{}).(int)}
+		})
+		cnt.MustBindTransient(func(bound *testStruct, ctx context.Context) *dTestStruct {
+			assert.Equal(t, 1, ctx.Value(ctxKey{}))
+			return &dTestStruct{testStruct: bound}
+		})
+
+		var firstDTest *dTestStruct
+		ctx := context.WithValue(context.Background(), ctxKey{}, 1)
+		assert.NoError(t, cnt.ResolveContext(ctx, &firstDTest))
+		assert.Equal(t, 1, firstDTest.testStruct.intProp)
+		assert.NoError(t, cnt.Validate())
+	})
+
+	t.Run("resolve is aborted when context is done", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cnt.MustBindSingleton(func() *testStruct {
+			cancel()
+			return &testStruct{}
+		})
+		cnt.MustBindSingleton(func(bound *testStruct, other *testStruct) *dTestStruct {
+			t.Fatalf("should not be called")
+			return nil
+		})
+
+		var firstDTest *dTestStruct
+		err := cnt.ResolveContext(ctx, &firstDTest)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Nil(t, firstDTest)
+	})
+
+	t.Run("resolve with deadline doesn't wait for slow resolve", func(t *testing.T) {
+		cnt := CreateContainer()
+		started, release := make(chan struct{}), make(chan struct{})
+		cnt.MustBindSingleton(func() *testStruct {
+			close(started)
+			<-release
+			return &testStruct{intProp: 1}
+		})
+
+		slowDone := make(chan struct{})
+		go func() {
+			defer close(slowDone)
+			var bound *testStruct
+			assert.NoError(t, cnt.Resolve(&bound))
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		var bound *testStruct
+		err := cnt.ResolveContext(ctx, &bound)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Less(t, int64(time.Since(start)), int64(250*time.Millisecond))
+
+		close(release)
+		<-slowDone
+		testContainerMustResolve(t, cnt, &bound)
+		assert.Equal(t, 1, bound.intProp)
+	})
+
+	t.Run("resolve function returns error", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		errDial := errors.New("dial error")
+		ctr := 0
+		cnt.MustBindSingleton(func(ctx context.Context) (*testStruct, error) {
+			ctr++
+			if ctr == 1 {
+				return nil, errDial
+			}
+			return &testStruct{intProp: ctr}, nil
+		})
+
+		var bound *testStruct
+		assert.True(t, errors.Is(cnt.Resolve(&bound), errDial))
+		// Failed singleton is not saved, so it can be resolved again.
+		testContainerMustResolve(t, cnt, &bound)
+		assert.Equal(t, 2, bound.intProp)
+	})
+
+	t.Run("bind function with invalid second output", func(t *testing.T) {
+		defer checkMustPanic(t)
+
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() (*testStruct, int) { return nil, 0 })
+	})
+}
+
+func TestContainer_Call(t *testing.T) {
+	t.Run("call function with dependencies", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var boundStruct = &testStruct{intProp: 1}
+		cnt.MustBindSingleton(func() *testStruct { return boundStruct })
+
+		outputs, err := cnt.Call(func(bound *testStruct, ctx context.Context) (int, error) {
+			assert.NotNil(t, ctx)
+			return bound.intProp, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{1, nil}, outputs)
+	})
+
+	t.Run("call function with missing dependencies", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		_, err := cnt.Call(func(bound *testStruct) {
+			t.Fatalf("should not be called")
+		})
+		assert.True(t, errors.Is(err, ErrNotRegistered))
+	})
+
+	t.Run("call non function", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		_, err := cnt.Call(&testStruct{})
+		assert.Error(t, err)
+	})
+}
+
+// testWithin fails the test if fn doesn't return within a second.
+func testWithin(t *testing.T, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("should return within a second")
+	}
+}
+
+func TestContainer_ConcurrentResolve(t *testing.T) {
+	t.Run("resolve function resolves from its own container", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindSingleton(func() dTestInterface {
+			var s *testStruct
+			cnt.MustResolve(&s)
+			return &dTestStruct{testStruct: s}
+		})
+
+		testWithin(t, func() {
+			var d dTestInterface
+			testContainerMustResolve(t, cnt, &d)
+			assert.Equal(t, 1, d.GetIntProp())
+		})
+	})
+
+	t.Run("singleton is constructed once", func(t *testing.T) {
+		cnt := CreateContainer()
+		var ctr int32
+		cnt.MustBindSingleton(func() *testStruct {
+			atomic.AddInt32(&ctr, 1)
+			time.Sleep(10 * time.Millisecond)
+			return &testStruct{}
+		})
+
+		var wg sync.WaitGroup
+		instances := make([]*testStruct, 10)
+		for i := range instances {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, cnt.Resolve(&instances[i]))
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&ctr))
+		for _, instance := range instances {
+			assert.Same(t, instances[0], instance)
+		}
+	})
+
+	t.Run("slow resolve function doesn't block other scope", func(t *testing.T) {
+		cnt := CreateContainer()
+		started, release := make(chan struct{}), make(chan struct{})
+		cnt.MustBindScoped(func(ctx context.Context) *testStruct {
+			if ctx.Value(testSlowKey{}) != nil {
+				close(started)
+				<-release
+			}
+			return &testStruct{}
+		})
+
+		go func() {
+			var s *testStruct
+			_ = cnt.CreateScope().ResolveContext(context.WithValue(context.Background(), testSlowKey{}, true), &s)
+		}()
+		defer close(release)
+		<-started
+
+		testWithin(t, func() {
+			var s *testStruct
+			testContainerMustResolve(t, cnt.CreateScope(), &s)
+		})
+	})
+
+	t.Run("circular singletons", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func(d dTestInterface) *testStruct { return &testStruct{} })
+		cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		testWithin(t, func() {
+			var s *testStruct
+			assert.True(t, errors.Is(cnt.Resolve(&s), ErrCircularDependency))
+		})
+	})
+
+	t.Run("circular resolve from resolve function", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() (*testStruct, error) {
+			var d dTestInterface
+			if err := cnt.Resolve(&d); err != nil {
+				return nil, err
+			}
+			return &testStruct{}, nil
+		})
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		testWithin(t, func() {
+			var s *testStruct
+			assert.True(t, errors.Is(cnt.Resolve(&s), ErrCircularDependency))
+		})
+	})
+
+	t.Run("circular resolves from resolve functions in different goroutines", func(t *testing.T) {
+		cnt := CreateContainer()
+		startedS, startedD := make(chan struct{}), make(chan struct{})
+		cnt.MustBindSingleton(func() (*testStruct, error) {
+			close(startedS)
+			<-startedD
+			var d dTestInterface
+			if err := cnt.Resolve(&d); err != nil {
+				return nil, err
+			}
+			return &testStruct{}, nil
+		})
+		cnt.MustBindSingleton(func() (dTestInterface, error) {
+			close(startedD)
+			<-startedS
+			var s *testStruct
+			if err := cnt.Resolve(&s); err != nil {
+				return nil, err
+			}
+			return &dTestStruct{testStruct: s}, nil
+		})
+
+		testWithin(t, func() {
+			errs := make(chan error, 1)
+			go func() {
+				var d dTestInterface
+				errs <- cnt.Resolve(&d)
+			}()
+			var s *testStruct
+			assert.True(t, errors.Is(cnt.Resolve(&s), ErrCircularDependency))
+			assert.True(t, errors.Is(<-errs, ErrCircularDependency))
+		})
+	})
+}
+
+type testSlowKey struct{}
+
+// warmSingletonResolveAllocs is allocation budget of resolving singleton that is already instantiated.
+// The only allocation is resolve option, which escapes as it is passed to option functions.
+const warmSingletonResolveAllocs = 1
+
+func TestContainer_ResolveAllocs(t *testing.T) {
+	t.Run("warm singleton resolve stays within budget", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = cnt.Resolve(&d)
+		})
+		if allocs > warmSingletonResolveAllocs {
+			t.Fatalf("warm singleton resolve allocates %v times, budget is %v", allocs, warmSingletonResolveAllocs)
+		}
+	})
+}
+
+// bindChain binds transient chain of depth distinct types, each depends on the previous one, and returns pointer to
+// receiver of the last type.
+func bindChain(cnt Container, depth int) interface{} {
+	var prev reflect.Type
+	for idx := 0; idx < depth; idx++ {
+		current := reflect.PtrTo(reflect.StructOf([]reflect.StructField{
+			{Name: fmt.Sprintf("Level%d", idx), Type: reflect.TypeOf(0)},
+		}))
+		var in []reflect.Type
+		if prev != nil {
+			in = []reflect.Type{prev}
+		}
+		fnType := reflect.FuncOf(in, []reflect.Type{current}, false)
+		fn := reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.New(current.Elem())}
+		})
+		cnt.MustBindTransient(fn.Interface())
+		prev = current
+	}
+
+	return reflect.New(prev).Interface()
+}
+
+func BenchmarkContainer_Bind(b *testing.B) {
+	cnt := CreateContainer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+	}
+}
+
+func BenchmarkContainer_ResolveSingleton(b *testing.B) {
+	b.Run("cold", func(b *testing.B) {
+		cnt := CreateContainer()
+		var d dTestInterface
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+			cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+			if err := cnt.Resolve(&d); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("warm", func(b *testing.B) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+		var d dTestInterface
+		cnt.MustResolve(&d)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := cnt.Resolve(&d); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkContainer_ResolveTransient(b *testing.B) {
+	for _, depth := range []int{1, 5, 20} {
+		b.Run(fmt.Sprintf("depth %d", depth), func(b *testing.B) {
+			cnt := CreateContainer()
+			receiver := bindChain(cnt, depth)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := cnt.Resolve(receiver); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkContainer_ResolveAlias(b *testing.B) {
+	cnt := CreateContainer()
+	aliases := make([]string, 100)
+	for idx := range aliases {
+		idx := idx
+		aliases[idx] = fmt.Sprintf("alias-%d", idx)
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: idx} }, WithBindAlias(aliases[idx]))
+	}
+
+	var s *testStruct
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := cnt.Resolve(&s, WithResolveAlias(aliases[i%len(aliases)])); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkContainer_ResolveConcurrent(b *testing.B) {
+	cnt := CreateContainer()
+	cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+	cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var d dTestInterface
+		for pb.Next() {
+			if err := cnt.Resolve(&d); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package ioc
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// ResolveFrame is a binding in dependency chain of ResolveError.
+type ResolveFrame struct {
+	// Label is name of the bound type.
+	Label string
+	Alias string
+	// Factory is name of resolve function, File and Line are its location. They are empty if binding is not found.
+	Factory string
+	File    string
+	Line    int
+}
+
+func (f ResolveFrame) String() string {
+	if f.Factory == "" {
+		return fmt.Sprintf("%v (alias %v)", f.Label, f.Alias)
+	}
+
+	return fmt.Sprintf("%v (alias %v) from %v at %v:%v", f.Label, f.Alias, f.Factory, f.File, f.Line)
+}
+
+// newResolveFrame creates frame of binding with given label and alias, b can be nil if binding is not found.
+func newResolveFrame(label, alias string, b *binder) ResolveFrame {
+	frame := ResolveFrame{Label: label, Alias: alias}
+	if b == nil {
+		return frame
+	}
+
+	frame.Factory, frame.File, frame.Line = funcLocation(b.resolveFunc)
+
+	return frame
+}
+
+// funcLocation returns name and location of function, empty if it is not known.
+func funcLocation(fn interface{}) (name, file string, line int) {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "", "", 0
+	}
+	file, line = f.FileLine(f.Entry())
+
+	return f.Name(), file, line
+}
+
+// ResolveError is returned when resolve fails, Chain is list of bindings from the resolved receiver to the binding
+// that fails, and Err is the cause that can be matched with errors.Is, such as ErrNotRegistered or ErrAliasNotKnown.
+// Use %+v to format each binding of the chain in its own line.
+type ResolveError struct {
+	Chain []ResolveFrame
+	Err   error
+}
+
+func (e *ResolveError) Error() string {
+	path := make([]string, 0, len(e.Chain))
+	for _, frame := range e.Chain {
+		path = append(path, frame.Label+"#"+frame.Alias)
+	}
+
+	return fmt.Sprintf("can't resolve %v, err: %v", strings.Join(path, " -> "), e.Err)
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ResolveError) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		fmt.Fprint(s, e.Error())
+		return
+	}
+
+	fmt.Fprintf(s, "%v\nresolving:", e.Err)
+	for _, frame := range e.Chain {
+		fmt.Fprintf(s, "\n    %v", frame)
+	}
+}
+
+// FactoryPanicError is returned when resolve function panics, Value is the recovered value, Stack is stack trace of
+// the panicking goroutine, and Chain is list of bindings from the resolved receiver to the one that panics.
+// Use %+v to format each binding of the chain in its own line, followed by the stack trace.
+type FactoryPanicError struct {
+	Value interface{}
+	Stack []byte
+	Chain []ResolveFrame
+}
+
+func (e *FactoryPanicError) Error() string {
+	path := make([]string, 0, len(e.Chain))
+	for _, frame := range e.Chain {
+		path = append(path, frame.Label+"#"+frame.Alias)
+	}
+
+	return fmt.Sprintf("can't resolve %v, resolve function panics: %v", strings.Join(path, " -> "), e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error, nil otherwise.
+func (e *FactoryPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+func (e *FactoryPanicError) Format(s fmt.State, verb rune) {
+	if verb != 'v' || !s.Flag('+') {
+		fmt.Fprint(s, e.Error())
+		return
+	}
+
+	fmt.Fprintf(s, "resolve function panics: %v\nresolving:", e.Value)
+	for _, frame := range e.Chain {
+		fmt.Fprintf(s, "\n    %v", frame)
+	}
+	fmt.Fprintf(s, "\n%s", e.Stack)
+}
+
+// withFrame returns copy of err with frame added to the start of its dependency chain, and wraps err into
+// ResolveError if it doesn't have dependency chain yet. err is never changed, as error of a construction is shared by
+// every resolve waiting for it.
+func withFrame(err error, frame ResolveFrame) error {
+	switch chainErr := err.(type) {
+	case *ResolveError:
+		return &ResolveError{Chain: append([]ResolveFrame{frame}, chainErr.Chain...), Err: chainErr.Err}
+	case *FactoryPanicError:
+		return &FactoryPanicError{
+			Value: chainErr.Value,
+			Stack: chainErr.Stack,
+			Chain: append([]ResolveFrame{frame}, chainErr.Chain...),
+		}
+	}
+
+	return &ResolveError{Chain: []ResolveFrame{frame}, Err: err}
+}
@@ -0,0 +1,166 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testChainStruct struct {
+	d dTestInterface
+}
+
+func newTestChainStruct(d dTestInterface) *testChainStruct {
+	return &testChainStruct{d: d}
+}
+
+func TestResolveError(t *testing.T) {
+	t.Run("missing dependency deep in the chain", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindTransient(newTestChainStruct)
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestTagStruct{testStruct: s} },
+			WithBindMeta(&dTestTagStruct{}))
+		cnt.MustBindTransient(func() *testStruct { return &testStruct{} })
+
+		var s *testChainStruct
+		err := cnt.Resolve(&s)
+		assert.True(t, errors.Is(err, ErrAliasNotKnown))
+
+		var resolveErr *ResolveError
+		if !assert.True(t, errors.As(err, &resolveErr)) {
+			return
+		}
+		assert.Len(t, resolveErr.Chain, 3)
+		assert.Equal(t, "*ioc.testChainStruct", resolveErr.Chain[0].Label)
+		assert.Equal(t, defaultAlias, resolveErr.Chain[0].Alias)
+		assert.True(t, strings.HasSuffix(resolveErr.Chain[0].Factory, "newTestChainStruct"))
+		assert.True(t, strings.HasSuffix(resolveErr.Chain[0].File, "error_test.go"))
+		assert.NotZero(t, resolveErr.Chain[0].Line)
+		assert.Equal(t, "ioc.dTestInterface", resolveErr.Chain[1].Label)
+		assert.Equal(t, ResolveFrame{Label: "*ioc.testStruct", Alias: "test"}, resolveErr.Chain[2])
+
+		assert.True(t, strings.HasPrefix(err.Error(),
+			"can't resolve *ioc.testChainStruct#default -> ioc.dTestInterface#default -> *ioc.testStruct#test, err: "))
+		lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+		assert.Len(t, lines, 5)
+		assert.Equal(t, "resolving:", lines[1])
+		assert.Equal(t, "    *ioc.testStruct (alias test)", lines[4])
+		assert.Equal(t, err.Error(), fmt.Sprintf("%v", err))
+	})
+
+	t.Run("receiver is not bound", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var s *testStruct
+		err := cnt.Resolve(&s)
+		assert.True(t, errors.Is(err, ErrNotRegistered))
+
+		var resolveErr *ResolveError
+		assert.True(t, errors.As(err, &resolveErr))
+		assert.Equal(t, []ResolveFrame{{Label: "*ioc.testStruct", Alias: defaultAlias}}, resolveErr.Chain)
+	})
+
+	t.Run("resolve function returns error", func(t *testing.T) {
+		cnt := CreateContainer()
+		expected := errors.New("failed")
+		cnt.MustBindTransient(func() (*testStruct, error) { return nil, expected })
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var d dTestInterface
+		err := cnt.Resolve(&d)
+		assert.True(t, errors.Is(err, expected))
+
+		var resolveErr *ResolveError
+		assert.True(t, errors.As(err, &resolveErr))
+		assert.Len(t, resolveErr.Chain, 2)
+	})
+
+	t.Run("concurrent resolves of failing singleton", func(t *testing.T) {
+		cnt := CreateContainer()
+		expected := errors.New("failed")
+		release := make(chan struct{})
+		cnt.MustBindTransient(func() (*testStruct, error) {
+			<-release
+			return nil, expected
+		})
+		cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		errs := make([]error, 6)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var d dTestInterface
+				errs[i] = cnt.Resolve(&d)
+			}(i)
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.True(t, errors.Is(err, expected))
+			var resolveErr *ResolveError
+			if assert.True(t, errors.As(err, &resolveErr)) && assert.Len(t, resolveErr.Chain, 2) {
+				assert.Equal(t, "ioc.dTestInterface", resolveErr.Chain[0].Label)
+				assert.Equal(t, "*ioc.testStruct", resolveErr.Chain[1].Label)
+			}
+		}
+	})
+}
+
+func TestFactoryPanicError(t *testing.T) {
+	t.Run("recover panic of dependency", func(t *testing.T) {
+		cnt := CreateContainer()
+		calls := 0
+		cnt.MustBindSingleton(func() *testStruct {
+			calls++
+			if calls == 1 {
+				panic("failed")
+			}
+			return &testStruct{intProp: 1}
+		})
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var d dTestInterface
+		err := cnt.Resolve(&d)
+
+		var panicErr *FactoryPanicError
+		if !assert.True(t, errors.As(err, &panicErr)) {
+			return
+		}
+		assert.Equal(t, "failed", panicErr.Value)
+		assert.Contains(t, string(panicErr.Stack), "error_test.go")
+		assert.Len(t, panicErr.Chain, 2)
+		assert.Equal(t, "can't resolve ioc.dTestInterface#default -> *ioc.testStruct#default, "+
+			"resolve function panics: failed", err.Error())
+		formatted := fmt.Sprintf("%+v", err)
+		assert.True(t, strings.HasPrefix(formatted, "resolve function panics: failed\nresolving:\n"))
+		assert.Contains(t, formatted, "goroutine")
+
+		// Singleton is not saved, so it can be resolved again.
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+	})
+
+	t.Run("recover panic of receiver", func(t *testing.T) {
+		cnt := CreateContainer()
+		expected := errors.New("failed")
+		cnt.MustBindTransient(func() *testStruct { panic(expected) })
+
+		var s *testStruct
+		err := cnt.Resolve(&s)
+		assert.True(t, errors.Is(err, expected))
+
+		var panicErr *FactoryPanicError
+		assert.True(t, errors.As(err, &panicErr))
+		assert.Len(t, panicErr.Chain, 1)
+		assert.Nil(t, s)
+	})
+}
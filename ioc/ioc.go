@@ -1,7 +1,14 @@
 package ioc
 
+import "context"
+
 var root = CreateContainer()
 
+// Root returns root / default container used by package level functions.
+func Root() Container {
+	return root
+}
+
 // Clear calls root Clear method.
 func Clear() {
 	root.Clear()
@@ -27,12 +34,92 @@ func MustBindTransient(resolver interface{}, opts ...BindOption) {
 	root.MustBindTransient(resolver, opts...)
 }
 
+// BindScoped calls root BindScoped method.
+func BindScoped(resolver interface{}, opts ...BindOption) error {
+	return root.BindScoped(resolver, opts...)
+}
+
+// MustBindScoped calls root MustBindScoped method.
+func MustBindScoped(resolver interface{}, opts ...BindOption) {
+	root.MustBindScoped(resolver, opts...)
+}
+
 // Resolve calls root Resolve method.
 func Resolve(receiver interface{}, opts ...ResolveOption) error {
 	return root.Resolve(receiver, opts...)
 }
 
+// ResolveContext calls root ResolveContext method.
+func ResolveContext(ctx context.Context, receiver interface{}, opts ...ResolveOption) error {
+	return root.ResolveContext(ctx, receiver, opts...)
+}
+
 // MustResolve calls root MustResolve method.
 func MustResolve(receiver interface{}, opts ...ResolveOption) {
 	root.MustResolve(receiver, opts...)
 }
+
+// Install calls root Install method.
+func Install(modules ...*Module) error {
+	return root.Install(modules...)
+}
+
+// MustInstall calls root MustInstall method.
+func MustInstall(modules ...*Module) {
+	root.MustInstall(modules...)
+}
+
+// Start calls root Start method.
+func Start(ctx context.Context) error {
+	return root.Start(ctx)
+}
+
+// Stop calls root Stop method.
+func Stop(ctx context.Context) error {
+	return root.Stop(ctx)
+}
+
+// Validate calls root Validate method.
+func Validate() error {
+	return root.Validate()
+}
+
+// CreateScope calls root CreateScope method.
+func CreateScope() Scope {
+	return root.CreateScope()
+}
+
+// Call calls root Call method.
+func Call(fn interface{}) ([]interface{}, error) {
+	return root.Call(fn)
+}
+
+// CallContext calls root CallContext method.
+func CallContext(ctx context.Context, fn interface{}) ([]interface{}, error) {
+	return root.CallContext(ctx, fn)
+}
+
+// Override calls root Override method.
+func Override(resolver interface{}, opts ...BindOption) (func(), error) {
+	return root.Override(resolver, opts...)
+}
+
+// Clone calls root Clone method.
+func Clone(opts ...ContainerOption) Container {
+	return root.Clone(opts...)
+}
+
+// Snapshot calls root Snapshot method.
+func Snapshot() *SnapshotToken {
+	return root.Snapshot()
+}
+
+// Restore calls root Restore method.
+func Restore(s *SnapshotToken, opts ...RestoreOption) error {
+	return root.Restore(s, opts...)
+}
+
+// Finalize calls root Finalize method.
+func Finalize() error {
+	return root.Finalize()
+}
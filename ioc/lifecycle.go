@@ -0,0 +1,203 @@
+package ioc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// DefaultHookTimeout is maximum duration of a hook function when Hook.Timeout is empty.
+const DefaultHookTimeout = 15 * time.Second
+
+var lifecycleLabel = getLabel(reflect.TypeOf((*Lifecycle)(nil)).Elem())
+
+// Hook is a pair of functions that will be called when container starts and stops.
+// Both functions are optional.
+type Hook struct {
+	// Name is used to identify the hook in returned errors.
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+	// Timeout limits duration of each function, DefaultHookTimeout will be used if empty.
+	Timeout time.Duration
+}
+
+// Lifecycle allows bindings to register hooks, can be used as dependency of any binding.
+type Lifecycle interface {
+	Append(Hook)
+}
+
+// Starter is implemented by singleton instance that needs to be started when container starts.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by singleton instance that needs to be stopped when container stops.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+type lifecycle struct {
+	// mu guards hooks and started, as resolve functions append hooks without holding the container lock.
+	mu sync.Mutex
+	// hooks is list of hooks ordered by their registration, which follows dependency order.
+	hooks []Hook
+	// started is number of hooks from the start of the list that are already started.
+	started int
+}
+
+func (l *lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+}
+
+// appendInstance registers Starter and Stopper implementation of instance as a hook.
+func (l *lifecycle) appendInstance(instance interface{}) {
+	starter, isStarter := instance.(Starter)
+	stopper, isStopper := instance.(Stopper)
+	if !isStarter && !isStopper {
+		return
+	}
+
+	hook := Hook{Name: fmt.Sprintf("%T", instance)}
+	if isStarter {
+		hook.OnStart = starter.Start
+	}
+	if isStopper {
+		hook.OnStop = stopper.Stop
+	}
+	l.Append(hook)
+}
+
+func runHookFunc(ctx context.Context, fn func(ctx context.Context) error, timeout time.Duration) error {
+	if fn == nil {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		// Prefers result of the function if it finishes at the same time.
+		select {
+		case err := <-errCh:
+			return err
+		default:
+			return ctx.Err()
+		}
+	}
+}
+
+// stop stops started hooks in reverse order and returns all errors.
+func (l *lifecycle) stop(ctx context.Context) error {
+	var errs multiError
+	for {
+		l.mu.Lock()
+		if l.started == 0 {
+			l.mu.Unlock()
+			break
+		}
+		l.started--
+		hook := l.hooks[l.started]
+		l.mu.Unlock()
+
+		if err := runHookFunc(ctx, hook.OnStop, hook.Timeout); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop hook %v, err: %w", hook.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// start starts hooks that are not started yet, rolls back all started hooks if any of them fails or ctx is done.
+func (l *lifecycle) start(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.started >= len(l.hooks) {
+			l.mu.Unlock()
+			return nil
+		}
+		hook := l.hooks[l.started]
+		l.mu.Unlock()
+
+		err := ctx.Err()
+		if err == nil {
+			err = runHookFunc(ctx, hook.OnStart, hook.Timeout)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to start hook %v, err: %w", hook.Name, err)
+			// Done ctx would abort the rollback, so each stop hook is only limited by its own timeout.
+			stopCtx := ctx
+			if ctx.Err() != nil {
+				stopCtx = context.Background()
+			}
+			if stopErr := l.stop(stopCtx); stopErr != nil {
+				return multiError{err, stopErr}
+			}
+
+			return err
+		}
+
+		l.mu.Lock()
+		l.started++
+		l.mu.Unlock()
+	}
+}
+
+// instantiateSingletons invokes every singleton binder, so their hooks are registered in dependency order.
+// Binders are collected while holding the lock, and invoked without it.
+func (c *container) instantiateSingletons(ctx context.Context) error {
+	var labels, aliases []string
+	var binders []*binder
+	c.mu.Lock()
+	_ = c.walkBinders(func(label, alias string, b *binder) error {
+		if b.lifetime == lifetimeSingleton {
+			labels, aliases, binders = append(labels, label), append(aliases, alias), append(binders, b)
+		}
+		return nil
+	})
+	c.mu.Unlock()
+
+	for idx, b := range binders {
+		if _, err := c.invoke(ctx, b); err != nil {
+			return fmt.Errorf("can't instantiate label %v with alias %v, err: %w", labels[idx], aliases[idx], err)
+		}
+	}
+
+	return nil
+}
+
+// Start instantiates all singletons and runs their start hooks in dependency order.
+// If any hook fails, already started hooks will be stopped in reverse order.
+func (c *container) Start(ctx context.Context) error {
+	if err := c.instantiateSingletons(ctx); err != nil {
+		return err
+	}
+
+	return c.lifecycle.start(ctx)
+}
+
+// Stop runs stop hooks of started bindings in reverse order.
+// All hooks will be stopped even if some of them fail, and the errors will be returned together.
+func (c *container) Stop(ctx context.Context) error {
+	return c.lifecycle.stop(ctx)
+}
@@ -0,0 +1,147 @@
+package ioc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLifecycleStruct struct {
+	name   string
+	events *[]string
+	err    error
+}
+
+func (s *testLifecycleStruct) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start "+s.name)
+	return s.err
+}
+
+func (s *testLifecycleStruct) Stop(ctx context.Context) error {
+	*s.events = append(*s.events, "stop "+s.name)
+	return nil
+}
+
+type testLifecycleDependentStruct struct {
+	*testLifecycleStruct
+}
+
+func TestContainer_StartStop(t *testing.T) {
+	t.Run("start and stop in dependency order", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var events []string
+		cnt.MustBindSingleton(func(dep *testLifecycleStruct) *testLifecycleDependentStruct {
+			return &testLifecycleDependentStruct{&testLifecycleStruct{name: "dependent", events: &events}}
+		})
+		cnt.MustBindSingleton(func() *testLifecycleStruct {
+			return &testLifecycleStruct{name: "dependency", events: &events}
+		})
+
+		assert.NoError(t, cnt.Start(context.Background()))
+		assert.NoError(t, cnt.Stop(context.Background()))
+		assert.Equal(t, []string{"start dependency", "start dependent", "stop dependent", "stop dependency"}, events)
+	})
+
+	t.Run("start and stop hooks from lifecycle dependency", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var events []string
+		cnt.MustBindSingleton(func(lc Lifecycle) *testStruct {
+			lc.Append(Hook{
+				OnStart: func(ctx context.Context) error {
+					events = append(events, "start")
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					events = append(events, "stop")
+					return nil
+				},
+			})
+
+			return &testStruct{}
+		})
+
+		assert.NoError(t, cnt.Start(context.Background()))
+		assert.NoError(t, cnt.Stop(context.Background()))
+		assert.Equal(t, []string{"start", "stop"}, events)
+	})
+
+	t.Run("rollback started hooks when start fails", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		errStart := errors.New("start error")
+		var events []string
+		cnt.MustBindSingleton(func(dep *testLifecycleStruct) *testLifecycleDependentStruct {
+			return &testLifecycleDependentStruct{&testLifecycleStruct{name: "dependent", events: &events, err: errStart}}
+		})
+		cnt.MustBindSingleton(func() *testLifecycleStruct {
+			return &testLifecycleStruct{name: "dependency", events: &events}
+		})
+
+		err := cnt.Start(context.Background())
+		assert.True(t, errors.Is(err, errStart))
+		assert.Equal(t, []string{"start dependency", "start dependent", "stop dependency"}, events)
+
+		assert.NoError(t, cnt.Stop(context.Background()))
+		assert.Len(t, events, 3)
+	})
+
+	t.Run("hook exceeds timeout", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func(lc Lifecycle) *testStruct {
+			lc.Append(Hook{
+				OnStart: func(ctx context.Context) error {
+					<-ctx.Done()
+					time.Sleep(10 * time.Millisecond)
+					return nil
+				},
+				Timeout: time.Millisecond,
+			})
+
+			return &testStruct{}
+		})
+
+		err := cnt.Start(context.Background())
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("stop runs all hooks and returns errors", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		errStop := errors.New("stop error")
+		var events []string
+		cnt.MustBindSingleton(func(lc Lifecycle) *testStruct {
+			lc.Append(Hook{Name: "first", OnStop: func(ctx context.Context) error {
+				events = append(events, "first")
+				return nil
+			}})
+			lc.Append(Hook{Name: "second", OnStop: func(ctx context.Context) error {
+				events = append(events, "second")
+				return errStop
+			}})
+
+			return &testStruct{}
+		})
+
+		assert.NoError(t, cnt.Start(context.Background()))
+		err := cnt.Stop(context.Background())
+		assert.True(t, errors.Is(err, errStop))
+		assert.Equal(t, []string{"second", "first"}, events)
+	})
+
+	t.Run("start fails to instantiate singleton", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func(bound *testStruct) *dTestStruct {
+			return &dTestStruct{testStruct: bound}
+		})
+
+		err := cnt.Start(context.Background())
+		assert.True(t, errors.Is(err, ErrNotRegistered))
+	})
+}
@@ -0,0 +1,10 @@
+package ioc
+
+import "time"
+
+// activityLogger logs activity of container, it is set by WithLogger when log/slog is available.
+type activityLogger interface {
+	logBind(label, alias string, l lifetime, resolveFunc interface{}, overwritten bool)
+	logResolve(label, alias string, l lifetime, duration time.Duration, cached bool, err error)
+	logDispose(disposed int, err error)
+}
@@ -0,0 +1,50 @@
+//go:build go1.21
+// +build go1.21
+
+package ioc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger logs activity of the container and its scopes to logger: every bind, resolve and scope disposal at
+// debug level, and bind that overwrites existing binding at warn level.
+func WithLogger(logger *slog.Logger) ContainerOption {
+	return func(o *containerOption) {
+		o.logger = &slogLogger{logger: logger}
+	}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) logBind(label, alias string, lt lifetime, resolveFunc interface{}, overwritten bool) {
+	level, msg := slog.LevelDebug, "ioc: bind"
+	if overwritten {
+		level, msg = slog.LevelWarn, "ioc: bind overwrites existing binding"
+	}
+	name, file, line := funcLocation(resolveFunc)
+	l.logger.LogAttrs(context.Background(), level, msg, slog.String("type", label), slog.String("alias", alias),
+		slog.String("lifetime", lt.String()), slog.String("factory", name), slog.String("source", file),
+		slog.Int("line", line))
+}
+
+func (l *slogLogger) logResolve(label, alias string, lt lifetime, duration time.Duration, cached bool, err error) {
+	attrs := []slog.Attr{slog.String("type", label), slog.String("alias", alias),
+		slog.String("lifetime", lt.String()), slog.Bool("cached", cached), slog.Duration("duration", duration)}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	l.logger.LogAttrs(context.Background(), slog.LevelDebug, "ioc: resolve", attrs...)
+}
+
+func (l *slogLogger) logDispose(disposed int, err error) {
+	attrs := []slog.Attr{slog.Int("disposed", disposed)}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	l.logger.LogAttrs(context.Background(), slog.LevelDebug, "ioc: dispose scope", attrs...)
+}
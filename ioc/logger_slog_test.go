@@ -0,0 +1,69 @@
+//go:build go1.21
+// +build go1.21
+
+package ioc
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFailingCloser struct{}
+
+func (*testFailingCloser) Close() error {
+	return errors.New("failed")
+}
+
+func testLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" || a.Key == "source" || a.Key == "line" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestContainer_WithLogger(t *testing.T) {
+	t.Run("log bind, overwrite and resolve", func(t *testing.T) {
+		var buf bytes.Buffer
+		cnt := CreateContainer(WithLogger(testLogger(&buf)))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{} })
+		cnt.MustBindTransient(func() *testStruct { return &testStruct{} })
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if !assert.Len(t, lines, 3) {
+			return
+		}
+		assert.True(t, strings.HasPrefix(lines[0], `level=DEBUG msg="ioc: bind" type=*ioc.testStruct alias=default `+
+			`lifetime=singleton factory=github.com/josephsalimin/go-simple-ioc/ioc.TestContainer_WithLogger.`))
+		assert.True(t, strings.HasPrefix(lines[1], `level=WARN msg="ioc: bind overwrites existing binding" `+
+			`type=*ioc.testStruct alias=default lifetime=transient`))
+		assert.Equal(t, `level=DEBUG msg="ioc: resolve" type=*ioc.testStruct alias=default lifetime=transient `+
+			`cached=false`, lines[2])
+	})
+
+	t.Run("log dispose", func(t *testing.T) {
+		var buf bytes.Buffer
+		cnt := CreateContainer(WithLogger(testLogger(&buf)))
+		cnt.MustBindScoped(func() *testFailingCloser { return &testFailingCloser{} })
+
+		scope := cnt.CreateScope()
+		var s *testFailingCloser
+		testContainerMustResolve(t, scope, &s)
+		buf.Reset()
+		assert.Error(t, scope.Dispose())
+		assert.Equal(t, `level=DEBUG msg="ioc: dispose scope" disposed=1 `+
+			`error="failed to dispose *ioc.testFailingCloser, err: failed"`+"\n", buf.String())
+	})
+}
@@ -0,0 +1,31 @@
+package ioc
+
+import "time"
+
+// Metrics records resolves of container, label is name of the bound type and lifetime is singleton, transient, or
+// scoped. Metrics is called by resolves from different goroutines concurrently.
+type Metrics interface {
+	// IncResolve counts resolve of binding, cached is true if saved singleton or scoped instance is returned without
+	// calling its resolve function.
+	IncResolve(label, alias, lifetime string, cached bool)
+	// ObserveConstruct records duration of successful resolve function call of binding, including its dependencies.
+	ObserveConstruct(label, alias, lifetime string, duration time.Duration)
+}
+
+// WithMetrics sets metrics that records every resolve of the container and its scopes.
+func WithMetrics(metrics Metrics) ContainerOption {
+	return func(o *containerOption) {
+		o.metrics = metrics
+	}
+}
+
+func (l lifetime) String() string {
+	switch l {
+	case lifetimeSingleton:
+		return "singleton"
+	case lifetimeScoped:
+		return "scoped"
+	default:
+		return "transient"
+	}
+}
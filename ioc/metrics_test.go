@@ -0,0 +1,38 @@
+package ioc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetrics struct {
+	resolves   []string
+	constructs []string
+}
+
+func (m *testMetrics) IncResolve(label, alias, lifetime string, cached bool) {
+	m.resolves = append(m.resolves, fmt.Sprintf("%v#%v %v cached=%v", label, alias, lifetime, cached))
+}
+
+func (m *testMetrics) ObserveConstruct(label, alias, lifetime string, duration time.Duration) {
+	m.constructs = append(m.constructs, fmt.Sprintf("%v#%v %v", label, alias, lifetime))
+}
+
+func TestContainer_Metrics(t *testing.T) {
+	metrics := &testMetrics{}
+	cnt := CreateContainer(WithMetrics(metrics))
+	cnt.MustBindScoped(func() *testStruct { return &testStruct{} })
+
+	scope := cnt.CreateScope()
+	var s *testStruct
+	testContainerMustResolve(t, scope, &s)
+	testContainerMustResolve(t, scope, &s)
+	assert.Equal(t, []string{
+		"*ioc.testStruct#default scoped cached=false",
+		"*ioc.testStruct#default scoped cached=true",
+	}, metrics.resolves)
+	assert.Equal(t, []string{"*ioc.testStruct#default scoped"}, metrics.constructs)
+}
@@ -0,0 +1,38 @@
+package ioc
+
+import "reflect"
+
+// MissingHandler is called when there is no binder of type t with alias, it returns resolve function of type t and
+// true to bind it as singleton, or false to report the dependency as missing.
+type MissingHandler func(t reflect.Type, alias string) (resolveFunc interface{}, ok bool)
+
+// WithMissingHandler sets handler that provides binder for missing dependencies, instead of returning
+// ErrNotRegistered or ErrAliasNotKnown. Scopes use handler of their root container.
+func WithMissingHandler(handler MissingHandler) ContainerOption {
+	return func(o *containerOption) {
+		o.missingHandler = handler
+	}
+}
+
+// findBinder is same as getBinder, but binds resolve function from missing handler of root container to it if
+// binder of type t is not found.
+func (c *container) findBinder(t reflect.Type, label, alias string) (*binder, error) {
+	b, err := c.getBinder(label, alias)
+	if err == nil {
+		return b, nil
+	}
+
+	root := c.root()
+	if root.option.missingHandler == nil {
+		return nil, err
+	}
+	resolveFunc, ok := root.option.missingHandler(t, alias)
+	if !ok {
+		return nil, err
+	}
+	if err := root.bind(resolveFunc, &bindOption{alias: alias, lifetime: lifetimeSingleton}); err != nil {
+		return nil, err
+	}
+
+	return root.getBinder(label, alias)
+}
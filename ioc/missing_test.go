@@ -0,0 +1,58 @@
+package ioc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_MissingHandler(t *testing.T) {
+	handler := func(t reflect.Type, alias string) (interface{}, bool) {
+		if t != reflect.TypeOf(&testStruct{}) {
+			return nil, false
+		}
+		return func() *testStruct { return &testStruct{intProp: len(alias)} }, true
+	}
+
+	t.Run("bind missing dependency from handler", func(t *testing.T) {
+		cnt := CreateContainer().Clone(WithMissingHandler(handler))
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, len(defaultAlias), d.GetIntProp())
+		assert.NoError(t, cnt.Validate())
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Same(t, d.(*dTestStruct).testStruct, s)
+		testContainerMustResolve(t, cnt, &s, WithResolveAlias("test"))
+		assert.Equal(t, len("test"), s.intProp)
+	})
+
+	t.Run("scope uses handler of root container", func(t *testing.T) {
+		cnt := CreateContainer().Clone(WithMissingHandler(handler))
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt.CreateScope(), &s)
+		assert.Equal(t, len(defaultAlias), s.intProp)
+	})
+
+	t.Run("handler declines missing dependency", func(t *testing.T) {
+		cnt := CreateContainer().Clone(WithMissingHandler(handler))
+
+		var d dTestInterface
+		assert.True(t, errors.Is(cnt.Resolve(&d), ErrNotRegistered))
+	})
+
+	t.Run("handler returns invalid resolve function", func(t *testing.T) {
+		cnt := CreateContainer().Clone(WithMissingHandler(func(reflect.Type, string) (interface{}, bool) {
+			return func() testStruct { return testStruct{} }, true
+		}))
+
+		var s *testStruct
+		assert.Error(t, cnt.Resolve(&s))
+	})
+}
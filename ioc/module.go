@@ -0,0 +1,176 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrModuleNameEmpty   = errors.New("module name must not be empty")
+	ErrModuleInstalled   = errors.New("module is already installed")
+	ErrModuleImportCycle = errors.New("module import cycle is detected")
+)
+
+// Binding is a deferred bind call that will be applied to container when the module holding it is installed.
+type Binding struct {
+	resolveFunc interface{}
+	opts        []BindOption
+	lifetime    lifetime
+}
+
+// Singleton creates Binding that will be bound using BindSingleton.
+func Singleton(resolveFunc interface{}, opts ...BindOption) Binding {
+	return Binding{resolveFunc: resolveFunc, opts: opts, lifetime: lifetimeSingleton}
+}
+
+// Transient creates Binding that will be bound using BindTransient.
+func Transient(resolveFunc interface{}, opts ...BindOption) Binding {
+	return Binding{resolveFunc: resolveFunc, opts: opts, lifetime: lifetimeTransient}
+}
+
+// Scoped creates Binding that will be bound using BindScoped.
+func Scoped(resolveFunc interface{}, opts ...BindOption) Binding {
+	return Binding{resolveFunc: resolveFunc, opts: opts, lifetime: lifetimeScoped}
+}
+
+// Module groups bindings into reusable unit that can be installed to any container.
+type Module struct {
+	// Name is unique name of the module, used to detect duplicate installs and to report errors.
+	Name string
+	// Bindings is list of binding provided by the module.
+	Bindings []Binding
+	// Private is list of binding that can only be resolved as dependency of bindings from the same module.
+	// Resolving it from container or from other module will returns ErrNotExported.
+	Private []Binding
+	// Imports is list of module needed by the module, will be installed before its own bindings.
+	// Imported module that is already installed will be skipped.
+	Imports []*Module
+}
+
+type moduleState int
+
+const (
+	moduleInstalling moduleState = iota + 1
+	moduleInstalled
+)
+
+// installState is bindings and installed modules of container before a module is installed, so they can be put back
+// if any of its bindings fails.
+type installState struct {
+	cnt      map[string]binderMap
+	profiles map[string]map[string]binderMap
+	pending  []*pendingBinding
+	modules  map[string]moduleState
+}
+
+// copyBinderMaps copies binder maps, but not the binders, as bind only replaces them.
+func copyBinderMaps(cnt map[string]binderMap) map[string]binderMap {
+	copied := make(map[string]binderMap, len(cnt))
+	for label, binders := range cnt {
+		copied[label] = make(binderMap, len(binders))
+		for alias, b := range binders {
+			copied[label][alias] = b
+		}
+	}
+
+	return copied
+}
+
+func (c *container) saveInstallState() *installState {
+	state := &installState{
+		cnt:      copyBinderMaps(c.cnt),
+		profiles: make(map[string]map[string]binderMap, len(c.profiles)),
+		pending:  append([]*pendingBinding(nil), c.pending...),
+		modules:  make(map[string]moduleState, len(c.modules)),
+	}
+	for profile, cnt := range c.profiles {
+		state.profiles[profile] = copyBinderMaps(cnt)
+	}
+	for name, moduleState := range c.modules {
+		state.modules[name] = moduleState
+	}
+
+	return state
+}
+
+func (c *container) restoreInstallState(state *installState) {
+	c.cnt = state.cnt
+	c.profiles = state.profiles
+	c.pending = state.pending
+	c.modules = state.modules
+	c.generation++
+}
+
+func (c *container) installBindings(m *Module, bindings []Binding, isPrivate bool) error {
+	for idx, b := range bindings {
+		o := &bindOption{alias: c.root().option.defaultAlias, lifetime: b.lifetime}
+		applyBindOption(o, b.opts)
+		o.module = m.Name
+		o.isPrivate = isPrivate
+
+		if err := c.bind(b.resolveFunc, o); err != nil {
+			return fmt.Errorf("can't install binding %v of module %v, err: %w", idx, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *container) install(m *Module, isImport bool) error {
+	if m.Name == "" {
+		return ErrModuleNameEmpty
+	}
+
+	switch c.modules[m.Name] {
+	case moduleInstalling:
+		return fmt.Errorf("can't install module %v, err: %w", m.Name, ErrModuleImportCycle)
+	case moduleInstalled:
+		if isImport {
+			return nil
+		}
+		return fmt.Errorf("can't install module %v, err: %w", m.Name, ErrModuleInstalled)
+	}
+
+	c.modules[m.Name] = moduleInstalling
+	for _, imported := range m.Imports {
+		if err := c.install(imported, true); err != nil {
+			return fmt.Errorf("can't install import of module %v, err: %w", m.Name, err)
+		}
+	}
+
+	if err := c.installBindings(m, m.Bindings, false); err != nil {
+		return err
+	}
+	if err := c.installBindings(m, m.Private, true); err != nil {
+		return err
+	}
+	c.modules[m.Name] = moduleInstalled
+
+	return nil
+}
+
+// Install binds all bindings from given modules and their imports to container.
+// Will returns ErrModuleInstalled if a module with the same name is installed twice, and any bind error
+// wrapped with the name of the module it came from. Bindings of the failed module and its imports are removed, so
+// it can be installed again, while modules given before it stay installed.
+func (c *container) Install(modules ...*Module) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, m := range modules {
+		state := c.saveInstallState()
+		if err := c.install(m, false); err != nil {
+			c.restoreInstallState(state)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MustInstall is same as Install, but will panic if error.
+func (c *container) MustInstall(modules ...*Module) {
+	if err := c.Install(modules...); err != nil {
+		panic(err)
+	}
+}
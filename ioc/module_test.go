@@ -0,0 +1,211 @@
+package ioc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Install(t *testing.T) {
+	t.Run("install module with bindings and imports", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var boundStruct = &testStruct{intProp: 1}
+		base := &Module{
+			Name:     "base",
+			Bindings: []Binding{Singleton(func() *testStruct { return boundStruct })},
+		}
+		service := &Module{
+			Name: "service",
+			Bindings: []Binding{
+				Transient(func(bound *testStruct) dTestInterface {
+					return &dTestStruct{testStruct: bound}
+				}, WithBindMeta(&dTestStruct{})),
+			},
+			Imports: []*Module{base},
+		}
+		cnt.MustInstall(service)
+
+		var firstDTest dTestInterface
+		testContainerMustResolve(t, cnt, &firstDTest)
+		assert.Equal(t, boundStruct, firstDTest.(*dTestStruct).testStruct)
+	})
+
+	t.Run("install shared import only once", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		ctr := 0
+		base := &Module{
+			Name: "base",
+			Bindings: []Binding{Singleton(func() *testStruct {
+				ctr++
+				return &testStruct{intProp: ctr}
+			})},
+		}
+		first := &Module{Name: "first", Imports: []*Module{base}}
+		second := &Module{Name: "second", Imports: []*Module{base}}
+		cnt.MustInstall(first, second)
+
+		var bound *testStruct
+		testContainerMustResolve(t, cnt, &bound)
+		testContainerMustResolve(t, cnt, &bound)
+		assert.Equal(t, 1, bound.intProp)
+	})
+
+	t.Run("install duplicate module", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		m := &Module{Name: "base", Bindings: []Binding{Singleton(func() *testStruct { return &testStruct{} })}}
+		cnt.MustInstall(m)
+
+		err := cnt.Install(m)
+		assert.True(t, errors.Is(err, ErrModuleInstalled))
+	})
+
+	t.Run("install module without name", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		err := cnt.Install(&Module{})
+		assert.True(t, errors.Is(err, ErrModuleNameEmpty))
+	})
+
+	t.Run("install module with import cycle", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		first := &Module{Name: "first"}
+		second := &Module{Name: "second", Imports: []*Module{first}}
+		first.Imports = []*Module{second}
+
+		err := cnt.Install(first)
+		assert.True(t, errors.Is(err, ErrModuleImportCycle))
+	})
+
+	t.Run("install module with failing binding", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		base := &Module{Name: "base", Bindings: []Binding{Singleton(&testStruct{})}}
+		err := cnt.Install(&Module{Name: "service", Imports: []*Module{base}})
+		if assert.Error(t, err) {
+			assert.True(t, strings.Contains(err.Error(), "module base"))
+		}
+
+		// Failed module can be installed again after being fixed.
+		base.Bindings = []Binding{Singleton(func() *testStruct { return &testStruct{} })}
+		assert.NoError(t, cnt.Install(base))
+	})
+
+	t.Run("failed install removes bindings of the module", func(t *testing.T) {
+		cnt := CreateContainer(WithDuplicateBind(DuplicateBindReject))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+
+		base := &Module{Name: "base", Bindings: []Binding{
+			Transient(func() *dTestStruct { return &dTestStruct{} }),
+		}}
+		service := &Module{Name: "service", Imports: []*Module{base}, Bindings: []Binding{
+			Transient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} }),
+			Singleton(func() *testStruct { return &testStruct{intProp: 2} }),
+		}}
+		assert.True(t, errors.Is(cnt.Install(service), ErrAlreadyBound))
+		var d dTestInterface
+		assert.True(t, errors.Is(cnt.Resolve(&d), ErrNotRegistered))
+		var ds *dTestStruct
+		assert.True(t, errors.Is(cnt.Resolve(&ds), ErrNotRegistered))
+
+		service.Bindings = service.Bindings[:1]
+		assert.NoError(t, cnt.Install(service))
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+		testContainerMustResolve(t, cnt, &ds)
+	})
+
+	t.Run("must install panics", func(t *testing.T) {
+		defer checkMustPanic(t)
+
+		cnt := CreateContainer()
+		cnt.MustInstall(&Module{})
+	})
+
+	t.Run("clear resets installed modules", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		m := &Module{Name: "base"}
+		cnt.MustInstall(m)
+		cnt.Clear()
+		assert.NoError(t, cnt.Install(m))
+	})
+}
+
+func TestContainer_InstallPrivate(t *testing.T) {
+	t.Run("private binding is resolvable by the same module", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var boundStruct = &testStruct{intProp: 1}
+		cnt.MustInstall(&Module{
+			Name: "storage",
+			Bindings: []Binding{
+				Singleton(func(bound *testStruct) dTestInterface {
+					return &dTestStruct{testStruct: bound}
+				}, WithBindMeta(&dTestStruct{})),
+			},
+			Private: []Binding{Singleton(func() *testStruct { return boundStruct })},
+		})
+
+		var firstDTest dTestInterface
+		testContainerMustResolve(t, cnt, &firstDTest)
+		assert.Equal(t, boundStruct, firstDTest.(*dTestStruct).testStruct)
+	})
+
+	t.Run("private binding is not resolvable from container", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustInstall(&Module{
+			Name:    "storage",
+			Private: []Binding{Singleton(func() *testStruct { return &testStruct{} })},
+		})
+
+		var bound *testStruct
+		err := cnt.Resolve(&bound)
+		assert.True(t, errors.Is(err, ErrNotExported))
+		assert.Nil(t, bound)
+	})
+
+	t.Run("private binding is not resolvable from other module", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		storage := &Module{
+			Name:    "storage",
+			Private: []Binding{Singleton(func() *testStruct { return &testStruct{} })},
+		}
+		cnt.MustInstall(&Module{
+			Name: "service",
+			Bindings: []Binding{
+				Singleton(func(bound *testStruct) *dTestStruct {
+					return &dTestStruct{testStruct: bound}
+				}),
+			},
+			Imports: []*Module{storage},
+		})
+
+		var firstDTest *dTestStruct
+		err := cnt.Resolve(&firstDTest)
+		assert.True(t, errors.Is(err, ErrNotExported))
+	})
+
+	t.Run("private binding is not resolvable from binding outside module", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustInstall(&Module{
+			Name:    "storage",
+			Private: []Binding{Singleton(func() *testStruct { return &testStruct{} })},
+		})
+		cnt.MustBindSingleton(func(bound *testStruct) *dTestStruct {
+			return &dTestStruct{testStruct: bound}
+		})
+
+		var firstDTest *dTestStruct
+		err := cnt.Resolve(&firstDTest)
+		assert.True(t, errors.Is(err, ErrNotExported))
+	})
+}
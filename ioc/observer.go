@@ -0,0 +1,28 @@
+package ioc
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Observer is notified around every resolve of a binding, including resolve of its dependencies, so callbacks of
+// dependencies are called between callbacks of the binding that depends on them.
+// Resolves from different goroutines are notified concurrently, so state of a resolve, such as a span, should be kept
+// in context returned by OnResolveStart, which is passed to resolve of its dependencies and to OnResolveEnd.
+type Observer interface {
+	// OnResolveStart is called before binding of type t and alias is resolved with ctx, and returns context used to
+	// resolve it.
+	OnResolveStart(ctx context.Context, t reflect.Type, alias string) context.Context
+	// OnResolveEnd is called after binding of type t and alias is resolved, cached is true if saved singleton or
+	// scoped instance is returned without calling its resolve function, and err is not nil if resolve fails.
+	// ctx is the context returned by OnResolveStart.
+	OnResolveEnd(ctx context.Context, t reflect.Type, alias string, duration time.Duration, cached bool, err error)
+}
+
+// WithObserver adds observer that is notified around every resolve of the container and its scopes.
+func WithObserver(observer Observer) ContainerOption {
+	return func(o *containerOption) {
+		o.observers = append(o.observers, observer)
+	}
+}
@@ -0,0 +1,63 @@
+package ioc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testObserver struct {
+	events []string
+}
+
+func (o *testObserver) OnResolveStart(ctx context.Context, t reflect.Type, alias string) context.Context {
+	o.events = append(o.events, fmt.Sprintf("start %v#%v", t, alias))
+	return ctx
+}
+
+func (o *testObserver) OnResolveEnd(ctx context.Context, t reflect.Type, alias string, duration time.Duration,
+	cached bool, err error) {
+	o.events = append(o.events, fmt.Sprintf("end %v#%v cached=%v err=%v", t, alias, cached, err != nil))
+}
+
+func TestContainer_Observer(t *testing.T) {
+	t.Run("observe nested resolves", func(t *testing.T) {
+		observer := &testObserver{}
+		cnt := CreateContainer(WithObserver(observer))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("test"))
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestTagStruct{testStruct: s} },
+			WithBindMeta(&dTestTagStruct{}))
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, []string{
+			"start ioc.dTestInterface#default",
+			"start *ioc.testStruct#test",
+			"end *ioc.testStruct#test cached=false err=false",
+			"end ioc.dTestInterface#default cached=false err=false",
+			"start ioc.dTestInterface#default",
+			"start *ioc.testStruct#test",
+			"end *ioc.testStruct#test cached=true err=false",
+			"end ioc.dTestInterface#default cached=false err=false",
+		}, observer.events)
+	})
+
+	t.Run("observe failed resolve from scope", func(t *testing.T) {
+		observer := &testObserver{}
+		cnt := CreateContainer(WithObserver(observer))
+		cnt.MustBindScoped(func() (*testStruct, error) { return nil, errors.New("failed") })
+
+		var s *testStruct
+		assert.Error(t, cnt.CreateScope().Resolve(&s))
+		assert.Equal(t, []string{
+			"start *ioc.testStruct#default",
+			"end *ioc.testStruct#default cached=false err=true",
+		}, observer.events)
+	})
+}
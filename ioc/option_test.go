@@ -0,0 +1,87 @@
+package ioc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dTestCustomTagStruct struct {
+	testStruct *testStruct `inject:"test"`
+}
+
+func (d *dTestCustomTagStruct) GetIntProp() int {
+	return d.testStruct.intProp
+}
+
+func TestCreateContainer_Options(t *testing.T) {
+	t.Run("custom tag key", func(t *testing.T) {
+		cnt := CreateContainer(WithTagKey("inject"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("test"))
+		cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestCustomTagStruct{testStruct: s} },
+			WithBindMeta(&dTestCustomTagStruct{}))
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+	})
+
+	t.Run("custom default alias", func(t *testing.T) {
+		cnt := CreateContainer(WithDefaultAlias("primary"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindTransient(func(s *testStruct, lc Lifecycle) dTestInterface {
+			return &dTestStruct{testStruct: s}
+		})
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s, WithResolveAlias("primary"))
+		assert.True(t, errors.Is(cnt.Resolve(&s, WithResolveAlias(defaultAlias)), ErrAliasNotKnown))
+		testContainerMustResolve(t, cnt.CreateScope(), &s)
+		testContainerMustResolve(t, cnt.Clone(), &s)
+	})
+
+	t.Run("strict mode rejects positional matching", func(t *testing.T) {
+		cnt := CreateContainer(WithStrict())
+
+		err := cnt.BindTransient(func(s1, s2 *testStruct) dTestInterface { return &dTestSameTypeStruct{} },
+			WithBindMeta(&dTestSameTypeStruct{}))
+		assert.True(t, errors.Is(err, ErrAmbiguousDependency))
+		err = cnt.BindTransient(func(s *testStruct) dTestInterface { return &dTestSameTypeStruct{} },
+			WithBindMeta(&dTestSameTypeStruct{}))
+		assert.True(t, errors.Is(err, ErrAmbiguousDependency))
+		_, err = cnt.Call(func(s1, s2 *testStruct) {})
+		assert.True(t, errors.Is(err, ErrAmbiguousDependency))
+		assert.NoError(t, cnt.BindTransient(func(s *testStruct) dTestInterface { return &dTestTagStruct{} },
+			WithBindMeta(&dTestTagStruct{})))
+	})
+
+	t.Run("extra meta fields are ignored without strict mode", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestSameTypeStruct{testStruct: s} },
+			WithBindMeta(&dTestSameTypeStruct{}))
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+	})
+
+	t.Run("reject duplicate bind", func(t *testing.T) {
+		cnt := CreateContainer(WithDuplicateBind(DuplicateBindReject))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+
+		err := cnt.BindSingleton(func() *testStruct { return &testStruct{intProp: 2} })
+		assert.True(t, errors.Is(err, ErrAlreadyBound))
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 1, s.intProp)
+
+		restore, err := cnt.Override(func() *testStruct { return &testStruct{intProp: 3} })
+		assert.NoError(t, err)
+		restore()
+	})
+}
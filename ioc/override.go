@@ -0,0 +1,148 @@
+package ioc
+
+import (
+	"reflect"
+	"sync"
+)
+
+// resetDependents resets instantiated singletons of container that depend on binder of given label and alias,
+// directly or indirectly, so they will be resolved again with the current binder.
+func (c *container) resetDependents(label, alias string) {
+	affected := map[[2]string]bool{{label, alias}: true}
+	// Config dependency depends on ConfigProvider.
+	configProvider := [2]string{getLabel(configProviderType), c.root().option.defaultAlias}
+	for changed := true; changed; {
+		changed = false
+		_ = c.walkBinders(func(label, alias string, b *binder) error {
+			key := [2]string{label, alias}
+			if affected[key] {
+				return nil
+			}
+			for _, dependency := range b.dependencies {
+				if isConfigDependency(dependency) {
+					dependency = configProvider
+				}
+				if !affected[dependency] {
+					continue
+				}
+				affected[key] = true
+				changed = true
+				if b.lifetime == lifetimeSingleton && b.owner == c {
+					b.instance = nil
+				}
+				break
+			}
+			return nil
+		})
+	}
+}
+
+// Override replaces binder of returned type of resolveFunc and alias with given resolveFunc, keeping lifetime and
+// module of the replaced binder. Instantiated singletons that depend on it are reset, so they will be resolved
+// again with the new binder.
+// Returns function that puts back the replaced binder, or removes the new binder if there was nothing to replace.
+func (c *container) Override(resolveFunc interface{}, opts ...BindOption) (func(), error) {
+	o := &bindOption{alias: c.root().option.defaultAlias, lifetime: lifetimeTransient}
+	applyBindOption(o, opts)
+	o.isOverride = true
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var label string
+	if resolveFuncType := reflect.TypeOf(resolveFunc); resolveFuncType != nil &&
+		resolveFuncType.Kind() == reflect.Func && resolveFuncType.NumOut() > 0 {
+		label = getLabel(resolveFuncType.Out(0))
+	}
+	original := c.cnt[label][o.alias]
+	// Override always replaces binder of active profiles.
+	o.profiles = nil
+	if original != nil {
+		o.lifetime = original.lifetime
+		o.module = original.module
+		o.isPrivate = original.isPrivate
+		o.profiles = original.profiles
+		o.isPrimary = original.isPrimary
+	}
+	if err := c.bind(resolveFunc, o); err != nil {
+		return nil, err
+	}
+	c.resetDependents(label, o.alias)
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if original == nil {
+			delete(c.cnt[label], o.alias)
+		} else {
+			if _, ok := c.cnt[label]; !ok {
+				c.cnt[label] = binderMap{}
+			}
+			c.cnt[label][o.alias] = original
+			// Override is kept under profiles of the original too.
+			c.bindProfiles(label, original)
+		}
+		c.generation++
+		c.resetDependents(label, o.alias)
+	}, nil
+}
+
+// Clone creates new root container with the same bindings and installed modules, but without instantiated
+// singletons and lifecycle hooks. Binding to the clone doesn't affect the container, and vice versa.
+// Bindings from parent of a scope are not cloned. Given opts are applied on top of option of the container.
+func (c *container) Clone(opts ...ContainerOption) Container {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clone := &container{mu: &sync.Mutex{}, option: c.root().option}
+	clone.option.observers = append([]Observer(nil), clone.option.observers...)
+	clone.option.profiles = append([]string(nil), clone.option.profiles...)
+	applyContainerOption(&clone.option, opts)
+	clone.clear()
+	_ = c.walkBinders(func(label, alias string, b *binder) error {
+		// Lifecycle binder is already bound by clear with lifecycle of the clone.
+		if label == lifecycleLabel && alias == clone.option.defaultAlias {
+			return nil
+		}
+		if !clone.isProfileActive(b.profiles) {
+			return nil
+		}
+
+		cloned := *b
+		cloned.owner = clone
+		cloned.instance = nil
+		if _, ok := clone.cnt[label]; !ok {
+			clone.cnt[label] = binderMap{}
+		}
+		clone.cnt[label][alias] = &cloned
+		return nil
+	})
+	clone.profiles = copyProfiles(c.profiles)
+	for _, cnt := range clone.profiles {
+		_ = walkBinderMap(cnt, func(label, alias string, b *binder) error {
+			b.owner = clone
+			b.instance = nil
+			return nil
+		})
+	}
+	clone.pending = append([]*pendingBinding(nil), c.pending...)
+	// Profiles of the clone may differ, so binders of its active profiles replace the copied ones.
+	for _, profile := range clone.option.profiles {
+		_ = walkBinderMap(clone.profiles[profile], func(label, alias string, b *binder) error {
+			if _, ok := clone.cnt[label]; !ok {
+				clone.cnt[label] = binderMap{}
+			}
+			if existing, ok := clone.cnt[label][alias]; ok && len(existing.profiles) == 0 {
+				clone.bindProfile(noProfile, label, existing)
+			}
+			clone.cnt[label][alias] = b
+			return nil
+		})
+	}
+	for name, state := range c.modules {
+		clone.modules[name] = state
+	}
+
+	return clone
+}
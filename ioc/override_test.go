@@ -0,0 +1,97 @@
+package ioc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Override(t *testing.T) {
+	t.Run("override resets dependent singletons", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindSingleton(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+
+		restore, err := cnt.Override(func() *testStruct { return &testStruct{intProp: 2} })
+		assert.NoError(t, err)
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 2, d.GetIntProp())
+
+		var s1, s2 *testStruct
+		testContainerMustResolve(t, cnt, &s1)
+		testContainerMustResolve(t, cnt, &s2)
+		assert.Same(t, s1, s2)
+
+		restore()
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+	})
+
+	t.Run("override keeps module of replaced binder", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustInstall(&Module{
+			Name:    "private",
+			Private: []Binding{Singleton(func() *testStruct { return &testStruct{intProp: 1} })},
+		})
+
+		restore, err := cnt.Override(func() *testStruct { return &testStruct{intProp: 2} })
+		assert.NoError(t, err)
+		defer restore()
+
+		var s *testStruct
+		assert.True(t, errors.Is(cnt.Resolve(&s), ErrNotExported))
+	})
+
+	t.Run("restore puts back binder of profile", func(t *testing.T) {
+		cnt := CreateContainer(WithProfile("test"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindProfile("test"))
+
+		restore, err := cnt.Override(func() *testStruct { return &testStruct{intProp: 2} })
+		assert.NoError(t, err)
+		restore()
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 1, s.intProp)
+		testContainerMustResolve(t, cnt.Clone(), &s)
+		assert.Equal(t, 1, s.intProp)
+	})
+
+	t.Run("override invalid function", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		_, err := cnt.Override(func() testStruct { return testStruct{} })
+		assert.Error(t, err)
+	})
+}
+
+func TestContainer_Clone(t *testing.T) {
+	cnt := CreateContainer()
+	cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+	cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} },
+		WithBindAlias("test"))
+
+	var s *testStruct
+	testContainerMustResolve(t, cnt, &s)
+
+	clone := cnt.Clone()
+	var cloned *testStruct
+	testContainerMustResolve(t, clone, &cloned)
+	assert.NotSame(t, s, cloned)
+
+	var d dTestInterface
+	testContainerMustResolve(t, clone, &d, WithResolveAlias("test"))
+	assert.Same(t, cloned, d.(*dTestStruct).testStruct)
+
+	clone.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindAlias("clone"))
+	assert.True(t, errors.Is(cnt.Resolve(&s, WithResolveAlias("clone")), ErrAliasNotKnown))
+
+	var lc Lifecycle
+	testContainerMustResolve(t, clone, &lc)
+	assert.Same(t, clone.(*container).lifecycle, lc)
+}
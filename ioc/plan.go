@@ -0,0 +1,152 @@
+package ioc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+)
+
+// plan is compiled form of binder for a container, so resolving it doesn't need to look up resolve function and
+// dependencies on every resolve.
+type plan struct {
+	// generation is generation of the container and its parents when the plan is compiled.
+	// The plan is stale once any of them is bound again.
+	generation uint64
+	// fn is cached value of the resolve function.
+	fn reflect.Value
+	// dependencies is list of binders of each parameter, nil for context parameter, and ConfigProvider binder for
+	// config parameter.
+	dependencies []*binder
+	// configs is list of config keys of each parameter, nil if none of the parameters is injected from configuration.
+	configs []*configKey
+	// arguments is pool of preallocated arguments of the resolve function, so concurrent resolves of the plan don't
+	// share them.
+	arguments sync.Pool
+}
+
+// chainGeneration returns sum of generation of container and its parents, it increases whenever any of them changes.
+func (c *container) chainGeneration() uint64 {
+	var generation uint64
+	for ; c != nil; c = c.parent {
+		generation += c.generation
+	}
+
+	return generation
+}
+
+// compilePlan looks up dependencies of binder from container and builds its plan.
+func (c *container) compilePlan(b *binder) (*plan, error) {
+	p := &plan{
+		generation:   c.chainGeneration(),
+		fn:           reflect.ValueOf(b.resolveFunc),
+		dependencies: make([]*binder, len(b.dependencies)),
+	}
+	p.arguments.New = func() interface{} {
+		in := make([]reflect.Value, len(b.dependencies))
+		return &in
+	}
+	for idx, dependency := range b.dependencies {
+		if isContextDependency(dependency) {
+			continue
+		}
+		if isConfigDependency(dependency) {
+			providerBinder, err := c.findConfigProvider()
+			if err != nil {
+				return nil, withFrame(err, newResolveFrame(dependency[0], dependency[1], nil))
+			}
+			if p.configs == nil {
+				p.configs = make([]*configKey, len(b.dependencies))
+			}
+			p.configs[idx] = parseConfigKey(dependency[1])
+			p.dependencies[idx] = providerBinder
+			continue
+		}
+
+		argBinder, err := c.findBinder(p.fn.Type().In(idx), dependency[0], dependency[1])
+		if err != nil {
+			return nil, withFrame(err, newResolveFrame(dependency[0], dependency[1], nil))
+		}
+		if err := checkExported(argBinder, b, dependency[0], dependency[1]); err != nil {
+			return nil, withFrame(err, newResolveFrame(dependency[0], dependency[1], argBinder))
+		}
+		p.dependencies[idx] = argBinder
+	}
+
+	return p, nil
+}
+
+// getPlan returns cached plan of binder for container, and compiles it again if it is stale.
+func (c *container) getPlan(b *binder) (*plan, error) {
+	generation := c.chainGeneration()
+	if p, ok := c.plans[b]; ok && p.generation == generation {
+		return p, nil
+	}
+
+	p, err := c.compilePlan(b)
+	if err != nil {
+		return nil, err
+	}
+	if c.plans == nil {
+		c.plans = map[*binder]*plan{}
+	}
+	c.plans[b] = p
+
+	return p, nil
+}
+
+// buildDependencyArguments resolves dependencies of plan into arguments in.
+func (c *container) buildDependencyArguments(ctx context.Context, b *binder, p *plan, in []reflect.Value) error {
+	for idx, argBinder := range p.dependencies {
+		dependency := b.dependencies[idx]
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("can't resolve dependencies from label %v with alias %v, err: %w",
+				dependency[0], dependency[1], err)
+		}
+		if argBinder == nil {
+			in[idx] = reflect.ValueOf(&ctx).Elem()
+			continue
+		}
+
+		res, err := c.invoke(ctx, argBinder)
+		if err != nil {
+			return withFrame(err, newResolveFrame(dependency[0], dependency[1], argBinder))
+		}
+		if p.configs != nil && p.configs[idx] != nil {
+			value, err := p.configs[idx].value(res.(ConfigProvider), p.fn.Type().In(idx))
+			if err != nil {
+				return withFrame(err, newResolveFrame(dependency[0], dependency[1], nil))
+			}
+			in[idx] = value
+			continue
+		}
+		in[idx] = reflect.ValueOf(res)
+	}
+
+	return nil
+}
+
+// call calls resolve function with given args, and recovers its panic into FactoryPanicError.
+func (p *plan) call(args []reflect.Value) (results []reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &FactoryPanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return p.fn.Call(args), nil
+}
+
+// acquireArguments takes preallocated arguments of the resolve function from pool of plan.
+func (p *plan) acquireArguments() *[]reflect.Value {
+	return p.arguments.Get().(*[]reflect.Value)
+}
+
+// releaseArguments clears arguments, so pool doesn't keep resolved instances alive, and puts them back to pool of plan.
+func (p *plan) releaseArguments(in *[]reflect.Value) {
+	for idx := range *in {
+		(*in)[idx] = reflect.Value{}
+	}
+	p.arguments.Put(in)
+}
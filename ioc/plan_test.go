@@ -0,0 +1,59 @@
+package ioc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Plan(t *testing.T) {
+	t.Run("plan is reused until rebind", func(t *testing.T) {
+		cnt := CreateContainer().(*container)
+		cnt.MustBindTransient(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+		p := cnt.plans[cnt.cnt["ioc.dTestInterface"][defaultAlias]]
+		testContainerMustResolve(t, cnt, &d)
+		assert.Same(t, p, cnt.plans[cnt.cnt["ioc.dTestInterface"][defaultAlias]])
+		for _, arg := range *p.acquireArguments() {
+			assert.False(t, arg.IsValid())
+		}
+
+		cnt.MustBindTransient(func() *testStruct { return &testStruct{intProp: 2} })
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 2, d.GetIntProp())
+	})
+
+	t.Run("scope plan is invalidated on parent rebind", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindTransient(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindScoped(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		scope := cnt.CreateScope()
+		var d dTestInterface
+		testContainerMustResolve(t, scope, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+
+		cnt.MustBindTransient(func() *testStruct { return &testStruct{intProp: 2} })
+		scope2 := cnt.CreateScope()
+		testContainerMustResolve(t, scope2, &d)
+		assert.Equal(t, 2, d.GetIntProp())
+	})
+
+	t.Run("plan is not cached if dependency is missing", func(t *testing.T) {
+		cnt := CreateContainer().(*container)
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var d dTestInterface
+		assert.True(t, errors.Is(cnt.Resolve(&d), ErrNotRegistered))
+		assert.Empty(t, cnt.plans)
+
+		cnt.MustBindTransient(func() *testStruct { return &testStruct{intProp: 1} })
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 1, d.GetIntProp())
+	})
+}
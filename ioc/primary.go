@@ -0,0 +1,73 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var ErrAmbiguousPrimary = errors.New("more than one binding is marked as primary")
+
+// WithBindPrimary marks binding as the choice when default alias of its type is requested but not bound,
+// so one of several aliased bindings can be used by consumers that don't know the aliases.
+func WithBindPrimary() BindOption {
+	return func(opt *bindOption) {
+		opt.isPrimary = true
+	}
+}
+
+// getPrimaryBinder returns binder of label marked as primary in the nearest container that has one, nil if there
+// is none. Returns ErrAmbiguousPrimary if the container has more than one of them.
+func (c *container) getPrimaryBinder(label string) (*binder, error) {
+	for cur := c; cur != nil; cur = cur.parent {
+		var aliases []string
+		for alias, b := range cur.cnt[label] {
+			if b.isPrimary {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		switch len(aliases) {
+		case 0:
+			continue
+		case 1:
+			return cur.cnt[label][aliases[0]], nil
+		default:
+			sort.Strings(aliases)
+			return nil, fmt.Errorf("can't choose primary of label %v from aliases %v, err: %w", label,
+				strings.Join(aliases, ", "), ErrAmbiguousPrimary)
+		}
+	}
+
+	return nil, nil
+}
+
+// findAliasBinder finds binder of the first alias of opt that is bound, trying each fallback alias in order.
+// Returns the alias of found binder.
+func (c *container) findAliasBinder(t reflect.Type, label string, opt *resolveOption) (*binder, string, error) {
+	if len(opt.fallbacks) == 0 {
+		b, err := c.findBinder(t, label, opt.alias)
+		return b, opt.alias, err
+	}
+
+	for _, alias := range append([]string{opt.alias}, opt.fallbacks...) {
+		b, err := c.getBinder(label, alias)
+		if err == nil {
+			return b, alias, nil
+		}
+		// Only unbound alias falls back to the next one, other errors such as ambiguous primary are reported.
+		if !errors.Is(err, ErrNotRegistered) && !errors.Is(err, ErrAliasNotKnown) {
+			return nil, alias, err
+		}
+	}
+	// None of the aliases is bound, so missing handler is only asked for the first one.
+	b, err := c.findBinder(t, label, opt.alias)
+	if err != nil {
+		return nil, opt.alias, fmt.Errorf("can't find dependencies from label %v with any of aliases %v, err: %w",
+			label, strings.Join(append([]string{opt.alias}, opt.fallbacks...), ", "), err)
+	}
+
+	return b, opt.alias, nil
+}
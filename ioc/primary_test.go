@@ -0,0 +1,96 @@
+package ioc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Primary(t *testing.T) {
+	t.Run("default alias resolves primary", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("eu"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindAlias("us"),
+			WithBindPrimary())
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 2, s.intProp)
+		var d dTestInterface
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 2, d.GetIntProp())
+		testContainerMustResolve(t, cnt, &s, WithResolveAlias("eu"))
+		assert.Equal(t, 1, s.intProp)
+		assert.NoError(t, cnt.Validate())
+	})
+
+	t.Run("bound default alias wins over primary", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("eu"),
+			WithBindPrimary())
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} })
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 2, s.intProp)
+	})
+
+	t.Run("more than one primary", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("eu"),
+			WithBindPrimary())
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindAlias("us"),
+			WithBindPrimary())
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+
+		var s *testStruct
+		err := cnt.Resolve(&s)
+		assert.True(t, errors.Is(err, ErrAmbiguousPrimary))
+		assert.Contains(t, err.Error(), "aliases eu, us")
+		assert.True(t, errors.Is(cnt.Validate(), ErrAmbiguousPrimary))
+	})
+
+	t.Run("primary of scope parent", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("eu"),
+			WithBindPrimary())
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt.CreateScope(), &s)
+		assert.Equal(t, 1, s.intProp)
+	})
+}
+
+func TestContainer_ResolveAliasFallback(t *testing.T) {
+	cnt := CreateContainer()
+	cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("global"))
+	cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindAlias("us"))
+
+	var s *testStruct
+	testContainerMustResolve(t, cnt, &s, WithResolveAlias("eu", "global"))
+	assert.Equal(t, 1, s.intProp)
+	testContainerMustResolve(t, cnt, &s, WithResolveAlias("us", "global"))
+	assert.Equal(t, 2, s.intProp)
+
+	err := cnt.Resolve(&s, WithResolveAlias("eu", "asia"))
+	assert.True(t, errors.Is(err, ErrAliasNotKnown))
+	assert.Contains(t, err.Error(), "any of aliases eu, asia")
+
+	var d dTestInterface
+	assert.True(t, errors.Is(cnt.Resolve(&d, WithResolveAlias("eu", "global")), ErrNotRegistered))
+
+	t.Run("ambiguous primary is not skipped", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindAlias("eu"),
+			WithBindPrimary())
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindAlias("us"),
+			WithBindPrimary())
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 3} }, WithBindAlias("global"))
+
+		var s *testStruct
+		err := cnt.Resolve(&s, WithResolveAlias("default", "global"))
+		assert.True(t, errors.Is(err, ErrAmbiguousPrimary))
+	})
+}
@@ -0,0 +1,163 @@
+package ioc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// WithProfile sets active profiles of container, binding with profiles is only resolved if one of them is active.
+func WithProfile(profiles ...string) ContainerOption {
+	return func(o *containerOption) {
+		o.profiles = append(o.profiles, profiles...)
+	}
+}
+
+// WithBindProfile makes binding active only when container is created with one of given profiles. Binding of
+// inactive profiles is kept aside, so it doesn't replace binding of the same type and alias, and is only used by
+// Validate to check graph of its profile. Binding of active profile takes precedence over binding of the same type and
+// alias without profile, whichever is bound first.
+func WithBindProfile(profiles ...string) BindOption {
+	return func(opt *bindOption) {
+		opt.profiles = append(opt.profiles, profiles...)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isProfileActive checks whether binding with given profiles is active in container.
+func (c *container) isProfileActive(profiles []string) bool {
+	if len(profiles) == 0 {
+		return true
+	}
+	for _, profile := range profiles {
+		if containsString(c.root().option.profiles, profile) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// noProfile is key of c.profiles for bindings without profile that are replaced by binding of active profile.
+// They are kept, so graph of other profiles can still be validated with them.
+const noProfile = ""
+
+// bindProfile keeps binder under given profile.
+func (c *container) bindProfile(profile, label string, b *binder) {
+	if c.profiles == nil {
+		c.profiles = map[string]map[string]binderMap{}
+	}
+	if _, ok := c.profiles[profile]; !ok {
+		c.profiles[profile] = map[string]binderMap{}
+	}
+	if _, ok := c.profiles[profile][label]; !ok {
+		c.profiles[profile][label] = binderMap{}
+	}
+	c.profiles[profile][label][b.alias] = b
+}
+
+// bindProfiles keeps binder under each of its profiles, so graph of each profile can be validated.
+func (c *container) bindProfiles(label string, b *binder) {
+	for _, profile := range b.profiles {
+		c.bindProfile(profile, label, b)
+	}
+}
+
+// bindFallback keeps binder without profile whose label and alias is bound by binding of active profile.
+func (c *container) bindFallback(label string, b *binder, duplicateBind DuplicateBindPolicy) error {
+	if _, ok := c.profiles[noProfile][label][b.alias]; ok && duplicateBind == DuplicateBindReject {
+		return fmt.Errorf("can't bind label %v with alias %v, err: %w", label, b.alias, ErrAlreadyBound)
+	}
+	c.bindProfile(noProfile, label, b)
+
+	return nil
+}
+
+// profileBinder finds binder of label and alias as if container is created with given profile.
+func (c *container) profileBinder(profile string, t reflect.Type, label, alias string) (*binder, error) {
+	for cur := c; cur != nil; cur = cur.parent {
+		if b, ok := cur.profiles[profile][label][alias]; ok {
+			return b, nil
+		}
+	}
+
+	b, err := c.findBinder(t, label, alias)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.profiles) > 0 && !containsString(b.profiles, profile) {
+		for cur := c; cur != nil; cur = cur.parent {
+			if fallback, ok := cur.profiles[noProfile][label][alias]; ok {
+				return fallback, nil
+			}
+		}
+		return nil, fmt.Errorf("can't find dependencies from label %v with alias %v in profile %v, err: %w", label,
+			alias, profile, ErrNotRegistered)
+	}
+
+	return b, nil
+}
+
+// profileBinders returns bindings of container as if it is created with given profile.
+func (c *container) profileBinders(profile string) map[string]binderMap {
+	binders := make(map[string]binderMap, len(c.cnt))
+	for label, aliases := range c.profiles[noProfile] {
+		binders[label] = binderMap{}
+		for alias, b := range aliases {
+			binders[label][alias] = b
+		}
+	}
+	for label, aliases := range c.cnt {
+		for alias, b := range aliases {
+			if len(b.profiles) > 0 && !containsString(b.profiles, profile) {
+				continue
+			}
+			if _, ok := binders[label]; !ok {
+				binders[label] = binderMap{}
+			}
+			binders[label][alias] = b
+		}
+	}
+	for label, aliases := range c.profiles[profile] {
+		if _, ok := binders[label]; !ok {
+			binders[label] = binderMap{}
+		}
+		for alias, b := range aliases {
+			binders[label][alias] = b
+		}
+	}
+
+	return binders
+}
+
+// inactiveProfiles returns sorted profiles of bindings in container which are not active.
+func (c *container) inactiveProfiles() []string {
+	profiles := make([]string, 0, len(c.profiles))
+	for profile := range c.profiles {
+		if profile != noProfile && !containsString(c.root().option.profiles, profile) {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Strings(profiles)
+
+	return profiles
+}
+
+// copyProfiles copies binders of each profile, so changing them doesn't affect the original.
+func copyProfiles(profiles map[string]map[string]binderMap) map[string]map[string]binderMap {
+	copied := make(map[string]map[string]binderMap, len(profiles))
+	for profile, cnt := range profiles {
+		copied[profile] = copyBinders(cnt)
+	}
+
+	return copied
+}
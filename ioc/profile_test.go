@@ -0,0 +1,113 @@
+package ioc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Profile(t *testing.T) {
+	bindProfiles := func(cnt Container) {
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindProfile("dev", "test"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindProfile("prod"))
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+	}
+
+	t.Run("resolve binding of active profile", func(t *testing.T) {
+		for profile, expected := range map[string]int{"dev": 1, "test": 1, "prod": 2} {
+			cnt := CreateContainer(WithProfile(profile))
+			bindProfiles(cnt)
+
+			var d dTestInterface
+			testContainerMustResolve(t, cnt, &d)
+			assert.Equal(t, expected, d.GetIntProp(), profile)
+		}
+	})
+
+	t.Run("binding of inactive profile is not resolved", func(t *testing.T) {
+		cnt := CreateContainer()
+		bindProfiles(cnt)
+
+		var s *testStruct
+		assert.True(t, errors.Is(cnt.Resolve(&s), ErrNotRegistered))
+	})
+
+	t.Run("inactive binding doesn't replace active binding", func(t *testing.T) {
+		cnt := CreateContainer(WithProfile("prod"), WithDuplicateBind(DuplicateBindReject))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 3} })
+		assert.NoError(t, cnt.BindSingleton(func() *testStruct { return &testStruct{intProp: 1} },
+			WithBindProfile("dev")))
+
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 3, s.intProp)
+	})
+
+	t.Run("active binding wins over binding without profile", func(t *testing.T) {
+		for _, policy := range []DuplicateBindPolicy{DuplicateBindOverwrite, DuplicateBindReject} {
+			cnt := CreateContainer(WithProfile("test"), WithDuplicateBind(policy))
+			cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindProfile("test"))
+			assert.NoError(t, cnt.BindSingleton(func() *testStruct { return &testStruct{intProp: 2} }))
+
+			other := CreateContainer(WithProfile("test"), WithDuplicateBind(policy))
+			other.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} })
+			assert.NoError(t, other.BindSingleton(func() *testStruct { return &testStruct{intProp: 1} },
+				WithBindProfile("test")))
+
+			for _, c := range []Container{cnt, other} {
+				var s *testStruct
+				testContainerMustResolve(t, c, &s)
+				assert.Equal(t, 1, s.intProp, policy)
+			}
+		}
+	})
+
+	t.Run("binding without profile is used by graph of other profile", func(t *testing.T) {
+		cnt := CreateContainer(WithProfile("test"), WithDuplicateBind(DuplicateBindReject))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindProfile("test"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} })
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} },
+			WithBindProfile("dev"))
+		assert.NoError(t, cnt.Validate())
+		assert.True(t, errors.Is(cnt.BindSingleton(func() *testStruct { return &testStruct{intProp: 3} }),
+			ErrAlreadyBound))
+	})
+
+	t.Run("validate each profile", func(t *testing.T) {
+		cnt := CreateContainer(WithProfile("prod"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} }, WithBindProfile("prod"))
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} }, WithBindProfile("dev"))
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+		assert.NoError(t, cnt.Validate())
+
+		cnt.MustBindTransient(func(d dTestInterface) *dTestTagStruct { return &dTestTagStruct{} },
+			WithBindProfile("test"))
+		err := cnt.Validate()
+		assert.True(t, errors.Is(err, ErrNotRegistered))
+		assert.Contains(t, err.Error(), "can't validate profile test")
+		assert.Contains(t, err.Error(), "*ioc.testStruct with alias default in profile test")
+		assert.NotContains(t, err.Error(), "profile dev")
+	})
+
+	t.Run("clone with other profile", func(t *testing.T) {
+		cnt := CreateContainer(WithProfile("prod"))
+		bindProfiles(cnt)
+
+		var d dTestInterface
+		testContainerMustResolve(t, cnt.Clone(WithProfile("test")), &d)
+		assert.Equal(t, 1, d.GetIntProp())
+		testContainerMustResolve(t, cnt, &d)
+		assert.Equal(t, 2, d.GetIntProp())
+	})
+
+	t.Run("clones don't share profiles", func(t *testing.T) {
+		cnt := CreateContainer(WithProfile("a"), WithProfile("b"), WithProfile("c"))
+		x := cnt.Clone(WithProfile("x")).(*container)
+		y := cnt.Clone(WithProfile("y")).(*container)
+
+		assert.Equal(t, []string{"a", "b", "c", "x"}, x.option.profiles)
+		assert.Equal(t, []string{"a", "b", "c", "y"}, y.option.profiles)
+		assert.Equal(t, []string{"a", "b", "c"}, cnt.(*container).option.profiles)
+	})
+}
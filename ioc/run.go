@@ -0,0 +1,130 @@
+package ioc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout is maximum duration to stop the container when Run is shutting down.
+const DefaultShutdownTimeout = 30 * time.Second
+
+var ErrNoRunSignals = errors.New("run needs at least one signal")
+
+type runOption struct {
+	signals         []os.Signal
+	shutdownTimeout time.Duration
+	exitCode        func(err error) int
+	errorHandler    func(err error)
+}
+
+type RunOption func(o *runOption)
+
+// WithRunSignals replaces signals that trigger shutdown, default is SIGINT and SIGTERM.
+// At least one signal must be given, as signal.Notify would catch every signal otherwise.
+func WithRunSignals(signals ...os.Signal) RunOption {
+	return func(o *runOption) {
+		o.signals = signals
+	}
+}
+
+// WithRunShutdownTimeout sets deadline to stop the container, default is DefaultShutdownTimeout.
+func WithRunShutdownTimeout(timeout time.Duration) RunOption {
+	return func(o *runOption) {
+		o.shutdownTimeout = timeout
+	}
+}
+
+// WithRunExitCode sets function that maps error from Run to exit code, default returns 0 if nil and 1 otherwise.
+func WithRunExitCode(exitCode func(err error) int) RunOption {
+	return func(o *runOption) {
+		o.exitCode = exitCode
+	}
+}
+
+// WithRunErrorHandler sets function that receives error from Run, default logs it using standard logger.
+func WithRunErrorHandler(errorHandler func(err error)) RunOption {
+	return func(o *runOption) {
+		o.errorHandler = errorHandler
+	}
+}
+
+func defaultExitCode(err error) int {
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+func applyRunOption(o *runOption, opts []RunOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+func run(ctx context.Context, c Container, o *runOption) error {
+	if len(o.signals) == 0 {
+		return ErrNoRunSignals
+	}
+
+	// Signals are caught before starting, so signal received while starting aborts Start and stops started hooks
+	// instead of killing the process.
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, o.signals...)
+	defer signal.Stop(signalCh)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-signalCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := c.Finalize(); err != nil {
+		return err
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if err := c.Start(ctx); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), o.shutdownTimeout)
+	defer cancel()
+
+	return c.Stop(stopCtx)
+}
+
+// Run finalizes, validates and starts the container, then blocks until one of the signals is received or ctx is done,
+// and finally stops the container within shutdown deadline. Signal received while starting aborts the start, and
+// hooks that are already started are stopped.
+// Returns exit code that can be passed to os.Exit.
+func Run(ctx context.Context, c Container, opts ...RunOption) int {
+	o := &runOption{
+		signals:         []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		shutdownTimeout: DefaultShutdownTimeout,
+		exitCode:        defaultExitCode,
+		errorHandler: func(err error) {
+			log.Println(err)
+		},
+	}
+	applyRunOption(o, opts)
+
+	err := run(ctx, c, o)
+	if err != nil {
+		o.errorHandler(err)
+	}
+
+	return o.exitCode(err)
+}
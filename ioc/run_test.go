@@ -0,0 +1,143 @@
+package ioc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("run until context is done", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var events []string
+		cnt.MustBindSingleton(func() *testLifecycleStruct {
+			return &testLifecycleStruct{name: "server", events: &events}
+		})
+		cnt.MustBindSingleton(func(lc Lifecycle, server *testLifecycleStruct) *testStruct {
+			lc.Append(Hook{OnStart: func(ctx context.Context) error {
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return nil
+			}})
+
+			return &testStruct{}
+		})
+
+		assert.Equal(t, 0, Run(ctx, cnt))
+		assert.Equal(t, []string{"start server", "stop server"}, events)
+	})
+
+	t.Run("run until signal is received", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		started := make(chan struct{})
+		cnt.MustBindSingleton(func(lc Lifecycle) *testStruct {
+			lc.Append(Hook{OnStart: func(ctx context.Context) error {
+				close(started)
+				return nil
+			}})
+
+			return &testStruct{}
+		})
+
+		go func() {
+			<-started
+			p, _ := os.FindProcess(os.Getpid())
+			_ = p.Signal(os.Interrupt)
+		}()
+
+		assert.Equal(t, 0, Run(context.Background(), cnt, WithRunSignals(os.Interrupt)))
+	})
+
+	t.Run("signal while starting stops started hooks", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var events []string
+		cnt.MustBindSingleton(func() *testLifecycleStruct {
+			return &testLifecycleStruct{name: "server", events: &events}
+		})
+		cnt.MustBindSingleton(func(lc Lifecycle, server *testLifecycleStruct) *testStruct {
+			lc.Append(Hook{
+				OnStart: func(ctx context.Context) error {
+					p, _ := os.FindProcess(os.Getpid())
+					_ = p.Signal(os.Interrupt)
+					<-ctx.Done()
+					return ctx.Err()
+				},
+				OnStop: func(ctx context.Context) error {
+					events = append(events, "stop slow")
+					return nil
+				},
+				Timeout: time.Second,
+			})
+
+			return &testStruct{}
+		})
+
+		var runErr error
+		code := Run(context.Background(), cnt, WithRunSignals(os.Interrupt),
+			WithRunErrorHandler(func(err error) { runErr = err }))
+		assert.Equal(t, 1, code)
+		assert.True(t, errors.Is(runErr, context.Canceled))
+		assert.Equal(t, []string{"start server", "stop server"}, events)
+	})
+
+	t.Run("run without signals", func(t *testing.T) {
+		var runErr error
+		code := Run(context.Background(), CreateContainer(), WithRunSignals(),
+			WithRunErrorHandler(func(err error) { runErr = err }))
+		assert.Equal(t, 1, code)
+		assert.True(t, errors.Is(runErr, ErrNoRunSignals))
+	})
+
+	t.Run("run invalid container", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func(bound *testStruct) *dTestStruct {
+			return &dTestStruct{testStruct: bound}
+		})
+
+		var runErr error
+		code := Run(context.Background(), cnt,
+			WithRunErrorHandler(func(err error) { runErr = err }),
+			WithRunExitCode(func(err error) int {
+				if errors.Is(err, ErrNotRegistered) {
+					return 2
+				}
+				return defaultExitCode(err)
+			}))
+		assert.Equal(t, 2, code)
+		assert.True(t, errors.Is(runErr, ErrNotRegistered))
+	})
+
+	t.Run("run stops within shutdown deadline", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cnt.MustBindSingleton(func(lc Lifecycle) *testStruct {
+			lc.Append(Hook{
+				OnStart: func(ctx context.Context) error {
+					time.AfterFunc(10*time.Millisecond, cancel)
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			})
+
+			return &testStruct{}
+		})
+
+		var runErr error
+		code := Run(ctx, cnt, WithRunShutdownTimeout(time.Millisecond),
+			WithRunErrorHandler(func(err error) { runErr = err }))
+		assert.Equal(t, 1, code)
+		assert.True(t, errors.Is(runErr, context.DeadlineExceeded))
+	})
+}
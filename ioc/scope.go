@@ -0,0 +1,83 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrScopeDisposed = errors.New("scope is already disposed")
+
+// Scope is a child container that keeps its own scoped instances, usually created for each request.
+// Bindings that are not found in the scope will be resolved from its parent.
+type Scope interface {
+	Container
+	Dispose() error
+}
+
+// CreateScope creates new scope from container.
+// Singletons are shared with the container, while scoped bindings will be instantiated once per scope.
+// Bindings bound to the scope itself are only visible to the scope.
+func (c *container) CreateScope() Scope {
+	return &container{
+		mu:        c.mu,
+		parent:    c,
+		lifecycle: &lifecycle{},
+		cnt:       map[string]binderMap{},
+		modules:   map[string]moduleState{},
+		scoped:    map[*binder]interface{}{},
+	}
+}
+
+// BindScoped binds given resolveFunc function and metadata information to container with scoped flag.
+// Scoped binding can only be resolved from a scope, and each scope will save its own instance.
+// First parameter must be a function that returns interface or pointer struct and meta can be nil or must implements
+// returned interface type from resolveFunc. The function may also returns error as second output, and may accept
+// context.Context parameter that will be filled with resolve context.
+func (c *container) BindScoped(resolveFunc interface{}, opts ...BindOption) error {
+	o := &bindOption{alias: c.root().option.defaultAlias, lifetime: lifetimeScoped}
+	applyBindOption(o, opts)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.bind(resolveFunc, o)
+}
+
+// MustBindScoped is same as BindScoped, but will panic if error.
+func (c *container) MustBindScoped(resolveFunc interface{}, opts ...BindOption) {
+	if err := c.BindScoped(resolveFunc, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// Dispose closes scoped instances that implement io.Closer in reverse order of their creation.
+// Scope can't be used to resolve anymore after disposed.
+func (c *container) Dispose() error {
+	c.mu.Lock()
+	if c.isDisposed {
+		c.mu.Unlock()
+		return ErrScopeDisposed
+	}
+	disposables := c.disposables
+	c.disposables = nil
+	c.scoped = map[*binder]interface{}{}
+	c.isDisposed = true
+	c.mu.Unlock()
+
+	var errs multiError
+	for idx := len(disposables) - 1; idx >= 0; idx-- {
+		if err := disposables[idx].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to dispose %T, err: %w", disposables[idx], err))
+		}
+	}
+
+	var err error
+	if len(errs) > 0 {
+		err = errs
+	}
+	if logger := c.root().option.logger; logger != nil {
+		logger.logDispose(len(disposables), err)
+	}
+
+	return err
+}
@@ -0,0 +1,138 @@
+package ioc
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClosableStruct struct {
+	name   string
+	events *[]string
+}
+
+func (s *testClosableStruct) Close() error {
+	*s.events = append(*s.events, "close "+s.name)
+	return nil
+}
+
+func TestContainer_CreateScope(t *testing.T) {
+	t.Run("scoped binding is saved per scope", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		ctr := 0
+		cnt.MustBindScoped(func() *testStruct {
+			ctr++
+			return &testStruct{intProp: ctr}
+		})
+
+		firstScope := cnt.CreateScope()
+		var first, second *testStruct
+		testContainerMustResolve(t, firstScope, &first)
+		testContainerMustResolve(t, firstScope, &second)
+		assert.Equal(t, first, second)
+
+		secondScope := cnt.CreateScope()
+		var third *testStruct
+		testContainerMustResolve(t, secondScope, &third)
+		assert.Equal(t, 2, third.intProp)
+	})
+
+	t.Run("singleton is shared between scopes", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+
+		var first, second *testStruct
+		testContainerMustResolve(t, cnt.CreateScope(), &first)
+		testContainerMustResolve(t, cnt.CreateScope(), &second)
+		assert.True(t, first == second)
+	})
+
+	t.Run("scope binding is visible to scope only", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindTransient(func(bound *testStruct) *dTestStruct {
+			return &dTestStruct{testStruct: bound}
+		})
+
+		scope := cnt.CreateScope()
+		var boundStruct = &testStruct{intProp: 1}
+		scope.MustBindSingleton(func() *testStruct { return boundStruct })
+
+		var firstDTest *dTestStruct
+		testContainerMustResolve(t, scope, &firstDTest)
+		assert.Equal(t, boundStruct, firstDTest.testStruct)
+
+		var secondDTest *dTestStruct
+		assert.True(t, errors.Is(cnt.Resolve(&secondDTest), ErrNotRegistered))
+	})
+
+	t.Run("scoped binding is not resolvable outside scope", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindScoped(func() *testStruct { return &testStruct{} })
+		cnt.MustBindSingleton(func(bound *testStruct) *dTestStruct {
+			return &dTestStruct{testStruct: bound}
+		})
+
+		var bound *testStruct
+		assert.True(t, errors.Is(cnt.Resolve(&bound), ErrNotInScope))
+
+		// Singleton can't capture scoped information even when resolved from a scope.
+		var firstDTest *dTestStruct
+		assert.True(t, errors.Is(cnt.CreateScope().Resolve(&firstDTest), ErrNotInScope))
+		assert.True(t, errors.Is(cnt.Validate(), ErrNotInScope))
+	})
+
+	t.Run("dispose closes scoped instances", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		var events []string
+		cnt.MustBindScoped(func() *testClosableStruct {
+			return &testClosableStruct{name: "first", events: &events}
+		})
+		cnt.MustBindScoped(func(first *testClosableStruct) dTestInterface {
+			return &dTestStruct{}
+		}, WithBindMeta(&dTestStruct{}))
+		cnt.MustBindScoped(func(first *testClosableStruct) *testClosableStruct {
+			return &testClosableStruct{name: "second", events: &events}
+		}, WithBindAlias("second"))
+
+		scope := cnt.CreateScope()
+		var closable *testClosableStruct
+		testContainerMustResolve(t, scope, &closable, WithResolveAlias("second"))
+		assert.NoError(t, scope.Dispose())
+		assert.Equal(t, []string{"close second", "close first"}, events)
+
+		assert.True(t, errors.Is(scope.Resolve(&closable), ErrScopeDisposed))
+		assert.True(t, errors.Is(scope.Dispose(), ErrScopeDisposed))
+	})
+
+	t.Run("resolve from multiple scopes concurrently", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		cnt.MustBindScoped(func(bound *testStruct) *dTestStruct {
+			return &dTestStruct{testStruct: bound}
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				scope := cnt.CreateScope()
+				defer scope.Dispose()
+
+				var firstDTest *dTestStruct
+				assert.NoError(t, scope.Resolve(&firstDTest))
+				assert.Equal(t, 1, firstDTest.GetIntProp())
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,133 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSnapshotMismatch = errors.New("snapshot is not taken from the container")
+	ErrContainerStarted = errors.New("container is started")
+)
+
+// SnapshotToken is opaque state of container bindings taken by Snapshot, which can be put back using Restore.
+type SnapshotToken struct {
+	owner    *container
+	cnt      map[string]binderMap
+	profiles map[string]map[string]binderMap
+	pending  []*pendingBinding
+	modules  map[string]moduleState
+	hooks    []Hook
+}
+
+type restoreOption struct {
+	instances bool
+}
+
+type RestoreOption func(o *restoreOption)
+
+// WithRestoreInstances restores singleton instances and lifecycle hooks as they were when the snapshot was taken,
+// instead of instantiating singletons again on next resolve. Restored hooks are not started, even if they were started
+// when the snapshot was taken, so they are started by next Start.
+func WithRestoreInstances() RestoreOption {
+	return func(o *restoreOption) {
+		o.instances = true
+	}
+}
+
+func applyRestoreOption(o *restoreOption, opts []RestoreOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// copyBinders copies binder maps and each binder, so changing them doesn't affect the original.
+func copyBinders(cnt map[string]binderMap) map[string]binderMap {
+	copied := make(map[string]binderMap, len(cnt))
+	for label, binders := range cnt {
+		copied[label] = make(binderMap, len(binders))
+		for alias, b := range binders {
+			cloned := *b
+			copied[label][alias] = &cloned
+		}
+	}
+
+	return copied
+}
+
+// Snapshot takes state of bindings, installed modules, singleton instances and lifecycle hooks of container.
+func (c *container) Snapshot() *SnapshotToken {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modules := make(map[string]moduleState, len(c.modules))
+	for name, state := range c.modules {
+		modules[name] = state
+	}
+
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+
+	return &SnapshotToken{
+		owner:    c,
+		cnt:      copyBinders(c.cnt),
+		profiles: copyProfiles(c.profiles),
+		pending:  append([]*pendingBinding(nil), c.pending...),
+		modules:  modules,
+		hooks:    append([]Hook(nil), c.lifecycle.hooks...),
+	}
+}
+
+// Restore puts back bindings and installed modules of container as they were when the snapshot was taken.
+// Singletons will be instantiated again on next resolve, unless WithRestoreInstances is given.
+// The same snapshot can be restored many times. Returns ErrSnapshotMismatch if snapshot is taken from other container,
+// and ErrContainerStarted if the container has started hooks, as they could not be stopped after restoring.
+func (c *container) Restore(s *SnapshotToken, opts ...RestoreOption) error {
+	o := &restoreOption{}
+	applyRestoreOption(o, opts)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s == nil || s.owner != c {
+		return ErrSnapshotMismatch
+	}
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+	if c.lifecycle.started > 0 {
+		return fmt.Errorf("can't restore snapshot, stop the container first, err: %w", ErrContainerStarted)
+	}
+
+	c.cnt = copyBinders(s.cnt)
+	c.profiles = copyProfiles(s.profiles)
+	c.pending = append([]*pendingBinding(nil), s.pending...)
+	if !o.instances {
+		_ = c.walkBinders(func(label, alias string, b *binder) error {
+			if b.lifetime == lifetimeSingleton {
+				b.instance = nil
+			}
+			return nil
+		})
+	}
+	// Lifecycle may be replaced by Clear after the snapshot is taken, so builtin binder always uses the current one.
+	if b, ok := c.cnt[lifecycleLabel][c.root().option.defaultAlias]; ok {
+		b.instance = c.lifecycle
+	}
+	if o.instances {
+		c.lifecycle.hooks = append([]Hook(nil), s.hooks...)
+	} else {
+		c.lifecycle.hooks = nil
+	}
+	// Container is not started when it is restored, so restored hooks are started again by next Start.
+	c.lifecycle.started = 0
+
+	c.modules = make(map[string]moduleState, len(s.modules))
+	for name, state := range s.modules {
+		c.modules[name] = state
+	}
+	c.scoped = map[*binder]interface{}{}
+	c.plans = map[*binder]*plan{}
+	c.generation++
+
+	return nil
+}
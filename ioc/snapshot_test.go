@@ -0,0 +1,140 @@
+package ioc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStartableStruct struct {
+	started *int
+}
+
+func (s *testStartableStruct) Start(ctx context.Context) error {
+	*s.started++
+	return nil
+}
+
+func TestContainer_Snapshot(t *testing.T) {
+	t.Run("restore bindings", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		token := cnt.Snapshot()
+
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 2} })
+		cnt.MustBindTransient(func(s *testStruct) dTestInterface { return &dTestStruct{testStruct: s} })
+		cnt.MustInstall(&Module{Name: "test"})
+
+		assert.NoError(t, cnt.Restore(token))
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 1, s.intProp)
+		var d dTestInterface
+		assert.True(t, errors.Is(cnt.Resolve(&d), ErrNotRegistered))
+		assert.NoError(t, cnt.Install(&Module{Name: "test"}))
+
+		// Same snapshot can be restored again.
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 3} })
+		assert.NoError(t, cnt.Restore(token))
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 1, s.intProp)
+	})
+
+	t.Run("restore after clear", func(t *testing.T) {
+		cnt := CreateContainer()
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{intProp: 1} })
+		token := cnt.Snapshot()
+
+		cnt.Clear()
+		assert.NoError(t, cnt.Restore(token))
+		var s *testStruct
+		testContainerMustResolve(t, cnt, &s)
+		assert.Equal(t, 1, s.intProp)
+		var lc Lifecycle
+		testContainerMustResolve(t, cnt, &lc)
+		assert.Same(t, cnt.(*container).lifecycle, lc)
+	})
+
+	t.Run("restore singletons are instantiated again", func(t *testing.T) {
+		cnt := CreateContainer()
+		started := 0
+		cnt.MustBindSingleton(func() *testStartableStruct { return &testStartableStruct{started: &started} })
+		var s *testStartableStruct
+		testContainerMustResolve(t, cnt, &s)
+		token := cnt.Snapshot()
+
+		assert.NoError(t, cnt.Restore(token))
+		var restored *testStartableStruct
+		testContainerMustResolve(t, cnt, &restored)
+		assert.NotSame(t, s, restored)
+		assert.NoError(t, cnt.Start(context.Background()))
+		assert.Equal(t, 1, started)
+	})
+
+	t.Run("restore with instances", func(t *testing.T) {
+		cnt := CreateContainer()
+		started := 0
+		cnt.MustBindSingleton(func() *testStartableStruct { return &testStartableStruct{started: &started} })
+		var s *testStartableStruct
+		testContainerMustResolve(t, cnt, &s)
+		token := cnt.Snapshot()
+
+		cnt.MustBindSingleton(func() *testStartableStruct { return &testStartableStruct{started: &started} })
+		testContainerMustResolve(t, cnt, &s)
+		assert.NoError(t, cnt.Restore(token, WithRestoreInstances()))
+
+		var restored *testStartableStruct
+		testContainerMustResolve(t, cnt, &restored)
+		assert.NotSame(t, s, restored)
+		assert.NoError(t, cnt.Start(context.Background()))
+		assert.Equal(t, 1, started)
+
+		var again *testStartableStruct
+		assert.NoError(t, cnt.Stop(context.Background()))
+		assert.NoError(t, cnt.Restore(token, WithRestoreInstances()))
+		testContainerMustResolve(t, cnt, &again)
+		assert.Same(t, restored, again)
+	})
+
+	t.Run("restore after start", func(t *testing.T) {
+		cnt := CreateContainer()
+		var events []string
+		cnt.MustBindSingleton(func() *testLifecycleStruct {
+			return &testLifecycleStruct{name: "server", events: &events}
+		})
+		token := cnt.Snapshot()
+		assert.NoError(t, cnt.Start(context.Background()))
+
+		assert.True(t, errors.Is(cnt.Restore(token), ErrContainerStarted))
+		assert.True(t, errors.Is(cnt.Restore(token, WithRestoreInstances()), ErrContainerStarted))
+		assert.NoError(t, cnt.Stop(context.Background()))
+		assert.Equal(t, []string{"start server", "stop server"}, events)
+		assert.NoError(t, cnt.Restore(token))
+	})
+
+	t.Run("restore with instances of started container", func(t *testing.T) {
+		cnt := CreateContainer()
+		var events []string
+		cnt.MustBindSingleton(func() *testLifecycleStruct {
+			return &testLifecycleStruct{name: "server", events: &events}
+		})
+		assert.NoError(t, cnt.Start(context.Background()))
+		token := cnt.Snapshot()
+		assert.NoError(t, cnt.Stop(context.Background()))
+
+		assert.NoError(t, cnt.Restore(token, WithRestoreInstances()))
+		assert.NoError(t, cnt.Start(context.Background()))
+		assert.NoError(t, cnt.Stop(context.Background()))
+		assert.NoError(t, cnt.Stop(context.Background()))
+		assert.Equal(t, []string{"start server", "stop server", "start server", "stop server"}, events)
+	})
+
+	t.Run("restore snapshot of other container", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		assert.Equal(t, ErrSnapshotMismatch, cnt.Restore(CreateContainer().Snapshot()))
+		assert.Equal(t, ErrSnapshotMismatch, cnt.Restore(nil))
+	})
+}
@@ -0,0 +1,143 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrCircularDependency = errors.New("circular dependency is detected")
+
+type validateState int
+
+const (
+	validateVisiting validateState = iota + 1
+	validateDone
+)
+
+type validator struct {
+	c *container
+	// profile is profile whose graph is validated, empty for graph of active profiles.
+	profile string
+	states  map[*binder]validateState
+	// path is list of label and alias currently being visited, used to report circular dependency.
+	path []string
+}
+
+// find finds binder of label and alias in graph of the validated profile.
+func (v *validator) find(t reflect.Type, label, alias string) (*binder, error) {
+	if v.profile == "" {
+		return v.c.findBinder(t, label, alias)
+	}
+
+	return v.c.profileBinder(v.profile, t, label, alias)
+}
+
+// visit walks dependencies of binder to find circular dependency, missing dependencies are skipped
+// as they are reported separately.
+func (v *validator) visit(label, alias string, b *binder) error {
+	switch v.states[b] {
+	case validateDone:
+		return nil
+	case validateVisiting:
+		return fmt.Errorf("can't validate %v, err: %w", strings.Join(append(v.path, label+"#"+alias), " -> "),
+			ErrCircularDependency)
+	}
+
+	v.states[b] = validateVisiting
+	v.path = append(v.path, label+"#"+alias)
+	for idx, dependency := range b.dependencies {
+		if isContextDependency(dependency) || isConfigDependency(dependency) {
+			continue
+		}
+		argBinder, err := v.find(reflect.TypeOf(b.resolveFunc).In(idx), dependency[0], dependency[1])
+		if err != nil {
+			continue
+		}
+		if err := v.visit(dependency[0], dependency[1], argBinder); err != nil {
+			// Marks as done, so the same cycle is only reported once.
+			v.states[b] = validateDone
+			return err
+		}
+	}
+	v.path = v.path[:len(v.path)-1]
+	v.states[b] = validateDone
+
+	return nil
+}
+
+func (v *validator) validateDependencies(label, alias string, b *binder) error {
+	for idx, dependency := range b.dependencies {
+		if isContextDependency(dependency) {
+			continue
+		}
+		if isConfigDependency(dependency) {
+			if _, err := v.find(configProviderType, getLabel(configProviderType),
+				v.c.root().option.defaultAlias); err != nil {
+				return fmt.Errorf("can't validate config %v of label %v with alias %v, err: %w", dependency[1], label,
+					alias, err)
+			}
+			continue
+		}
+		argBinder, err := v.find(reflect.TypeOf(b.resolveFunc).In(idx), dependency[0], dependency[1])
+		if err != nil {
+			return fmt.Errorf("can't validate dependencies of label %v with alias %v, err: %w", label, alias, err)
+		}
+		if err := checkExported(argBinder, b, dependency[0], dependency[1]); err != nil {
+			return err
+		}
+		if b.lifetime == lifetimeSingleton && argBinder.lifetime == lifetimeScoped {
+			return fmt.Errorf("can't use scoped dependencies from label %v with alias %v in singleton label %v "+
+				"with alias %v, err: %w", dependency[0], dependency[1], label, alias, ErrNotInScope)
+		}
+	}
+
+	return nil
+}
+
+// validate checks graph of the validator profile, and appends found errors to errs.
+func (v *validator) validate(cnt map[string]binderMap, errs multiError) multiError {
+	_ = walkBinderMap(cnt, func(label, alias string, b *binder) error {
+		if err := v.validateDependencies(label, alias, b); err != nil {
+			errs = append(errs, v.withProfile(err))
+		}
+		if err := v.visit(label, alias, b); err != nil {
+			errs = append(errs, v.withProfile(err))
+			v.path = v.path[:0]
+		}
+
+		return nil
+	})
+
+	return errs
+}
+
+func (v *validator) withProfile(err error) error {
+	if v.profile == "" {
+		return err
+	}
+
+	return fmt.Errorf("can't validate profile %v, err: %w", v.profile, err)
+}
+
+// Validate checks that every dependency of all bindings is registered, accessible, and not circular,
+// without instantiating anything. Graph of each inactive profile is checked too, as if the container is created
+// with that profile.
+// Will returns all found errors together, each of them can be matched with errors.Is.
+func (c *container) Validate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := (&validator{c: c, states: map[*binder]validateState{}}).validate(c.cnt, nil)
+	for _, profile := range c.inactiveProfiles() {
+		v := &validator{c: c, profile: profile, states: map[*binder]validateState{}}
+		errs = v.validate(c.profileBinders(profile), errs)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package ioc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCircularStruct struct {
+	other *testOtherCircularStruct
+}
+
+type testOtherCircularStruct struct {
+	circular *testCircularStruct
+}
+
+func TestContainer_Validate(t *testing.T) {
+	t.Run("validate complete graph", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{} }, WithBindAlias("test"))
+		cnt.MustBindTransient(func(bound *testStruct) dTestInterface {
+			return &dTestTagStruct{testStruct: bound}
+		}, WithBindMeta(&dTestTagStruct{}))
+
+		assert.NoError(t, cnt.Validate())
+	})
+
+	t.Run("validate missing dependencies", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func() *testStruct { return &testStruct{} })
+		cnt.MustBindTransient(func(bound *testStruct) dTestInterface {
+			return &dTestTagStruct{testStruct: bound}
+		}, WithBindMeta(&dTestTagStruct{}))
+		cnt.MustBindTransient(func(bound dTestInterface) *dTestStruct {
+			return &dTestStruct{}
+		})
+
+		err := cnt.Validate()
+		assert.True(t, errors.Is(err, ErrAliasNotKnown))
+		// Only binder with missing dependency is reported.
+		assert.Len(t, err.(multiError), 1)
+	})
+
+	t.Run("validate private dependencies", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustInstall(&Module{
+			Name:    "storage",
+			Private: []Binding{Singleton(func() *testStruct { return &testStruct{} })},
+		})
+		cnt.MustBindSingleton(func(bound *testStruct) *dTestStruct {
+			return &dTestStruct{testStruct: bound}
+		})
+
+		assert.True(t, errors.Is(cnt.Validate(), ErrNotExported))
+	})
+
+	t.Run("validate circular dependencies", func(t *testing.T) {
+		cnt := CreateContainer()
+
+		cnt.MustBindSingleton(func(other *testOtherCircularStruct) *testCircularStruct {
+			return &testCircularStruct{other: other}
+		})
+		cnt.MustBindSingleton(func(circular *testCircularStruct) *testOtherCircularStruct {
+			return &testOtherCircularStruct{circular: circular}
+		})
+
+		err := cnt.Validate()
+		assert.True(t, errors.Is(err, ErrCircularDependency))
+		assert.Len(t, err.(multiError), 1)
+	})
+}
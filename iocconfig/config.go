@@ -0,0 +1,247 @@
+// Package iocconfig loads configuration struct from layered sources, such as files, environment variables and
+// flags, and binds it to ioc container as singleton.
+//
+// Fields are keyed by config tag, or by snake cased field name when the tag is empty, and fields of nested structs
+// are keyed by dotted path, such as db.max_conns. Tag options are:
+//   - required, loading fails if the field is still zero after all sources and default are applied
+//   - bind, nested struct is bound as singleton too, so it can be resolved without the whole config
+//
+// Field with default tag is set to the default value when none of the sources sets its key.
+package iocconfig
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+const (
+	tagKey        = "config"
+	defaultTagKey = "default"
+	requiredOpt   = "required"
+	bindOpt       = "bind"
+)
+
+var (
+	// ErrInvalidConfig is returned when config is not a pointer to struct.
+	ErrInvalidConfig = errors.New("config must be pointer to struct")
+	// ErrRequired is returned when required config fields are not set.
+	ErrRequired = errors.New("required config is not set")
+)
+
+type loader struct {
+	sources []Source
+	// defaults is map of key to default tag value of config fields.
+	defaults map[string]string
+	// missing is list of keys of required fields that are not set.
+	missing []string
+	// binds is list of pointers to nested structs with bind option.
+	binds []reflect.Value
+}
+
+// BindConfig loads cfg from sources, validates its required fields, and binds it as singleton into c.
+// Later sources override earlier ones, and nested structs with bind option are bound as singletons too.
+// The sources are also bound as ioc.ConfigProvider, so parameters can be injected by config key using config tag.
+func BindConfig(c ioc.Container, cfg interface{}, sources ...Source) error {
+	l, err := load(cfg, sources)
+	if err != nil {
+		return err
+	}
+
+	l.collectBinds(reflect.ValueOf(cfg).Elem())
+	for _, instance := range append([]reflect.Value{reflect.ValueOf(cfg)}, l.binds...) {
+		if err := c.BindSingleton(singletonFunc(instance)); err != nil {
+			return fmt.Errorf("can't bind config %v, err: %w", instance.Type(), err)
+		}
+	}
+	if err := c.BindSingleton(func() ioc.ConfigProvider { return &provider{l: l} }); err != nil {
+		return fmt.Errorf("can't bind config provider, err: %w", err)
+	}
+
+	return nil
+}
+
+// Load loads cfg from sources and validates its required fields, without binding it.
+func Load(cfg interface{}, sources ...Source) error {
+	_, err := load(cfg, sources)
+	return err
+}
+
+func load(cfg interface{}, sources []Source) (*loader, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't load config %T, err: %w", cfg, ErrInvalidConfig)
+	}
+
+	l := &loader{sources: sources, defaults: map[string]string{}}
+	for _, source := range sources {
+		if err := source.Load(); err != nil {
+			return nil, err
+		}
+	}
+	if err := l.populate(v.Elem(), ""); err != nil {
+		return nil, err
+	}
+	if len(l.missing) > 0 {
+		return nil, fmt.Errorf("can't load config %v, missing keys %v, err: %w", v.Type(),
+			strings.Join(l.missing, ", "), ErrRequired)
+	}
+
+	return l, nil
+}
+
+// lookup returns value of key from the last source that sets it.
+func (l *loader) lookup(key string) (string, bool) {
+	for idx := len(l.sources) - 1; idx >= 0; idx-- {
+		if value, ok := l.sources[idx].Lookup(key); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// populate sets fields of struct v from sources, with keys prefixed by prefix.
+func (l *loader) populate(v reflect.Value, prefix string) error {
+	for idx := 0; idx < v.NumField(); idx++ {
+		field := v.Type().Field(idx)
+		name, opts, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+		key := joinKey(prefix, name)
+		fieldValue := v.Field(idx)
+
+		if isNested(field.Type) {
+			if field.Anonymous && field.Tag.Get(tagKey) == "" {
+				key = prefix
+			}
+			if fieldValue.Kind() == reflect.Ptr {
+				if fieldValue.IsNil() {
+					fieldValue.Set(reflect.New(field.Type.Elem()))
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			if err := l.populate(fieldValue, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, hasDefault := field.Tag.Lookup(defaultTagKey)
+		if hasDefault {
+			l.defaults[key] = def
+		}
+		value, ok := l.lookup(key)
+		if !ok {
+			value, ok = def, hasDefault
+		}
+		if ok {
+			if err := setValue(fieldValue, value); err != nil {
+				return fmt.Errorf("can't set config %v from value %q, err: %w", key, value, err)
+			}
+		}
+		if opts[requiredOpt] && fieldValue.IsZero() {
+			l.missing = append(l.missing, key)
+		}
+	}
+
+	return nil
+}
+
+// collectBinds collects pointers to nested structs of v with bind option.
+func (l *loader) collectBinds(v reflect.Value) {
+	for idx := 0; idx < v.NumField(); idx++ {
+		field := v.Type().Field(idx)
+		_, opts, ok := fieldKey(field)
+		if !ok || !isNested(field.Type) {
+			continue
+		}
+
+		fieldValue := v.Field(idx)
+		if fieldValue.Kind() != reflect.Ptr {
+			fieldValue = fieldValue.Addr()
+		}
+		if opts[bindOpt] {
+			l.binds = append(l.binds, fieldValue)
+		}
+		l.collectBinds(fieldValue.Elem())
+	}
+}
+
+// fieldKey returns key name and tag options of field, ok is false if field is unexported or skipped with "-" tag.
+func fieldKey(field reflect.StructField) (name string, opts map[string]bool, ok bool) {
+	if field.PkgPath != "" && (!field.Anonymous || field.Type.Kind() == reflect.Ptr || !isNested(field.Type)) {
+		return "", nil, false
+	}
+	tag := strings.Split(field.Tag.Get(tagKey), ",")
+	if tag[0] == "-" {
+		return "", nil, false
+	}
+
+	name = tag[0]
+	if name == "" {
+		name = snakeCase(field.Name)
+	}
+	opts = map[string]bool{}
+	for _, opt := range tag[1:] {
+		opts[strings.TrimSpace(opt)] = true
+	}
+
+	return name, opts, true
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isNested returns true if t is struct, or pointer to struct, whose fields are loaded by their own keys.
+func isNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// singletonFunc returns resolve function that returns given instance.
+func singletonFunc(instance reflect.Value) interface{} {
+	fnType := reflect.FuncOf(nil, []reflect.Type{instance.Type()}, false)
+	return reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{instance}
+	}).Interface()
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
+
+// snakeCase converts field name into snake case, such as MaxConns into max_conns and HTTPPort into http_port.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for idx, r := range runes {
+		if idx > 0 && unicode.IsUpper(r) {
+			prev := runes[idx-1]
+			nextIsLower := idx+1 < len(runes) && unicode.IsLower(runes[idx+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package iocconfig
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type databaseConfig struct {
+	Host     string        `config:"host,required"`
+	Port     int           `default:"5432"`
+	MaxConns int           `default:"10"`
+	Timeout  time.Duration `default:"5s"`
+}
+
+type appConfig struct {
+	Name     string
+	Debug    bool
+	Tags     []string
+	Database databaseConfig `config:"db,bind"`
+	Cache    *cacheConfig   `config:",bind"`
+	internal string
+}
+
+type cacheConfig struct {
+	TTL time.Duration `config:"ttl"`
+}
+
+type port int
+
+type embeddedConfig struct {
+	port
+	databaseConfig
+}
+
+type repository struct {
+	db *databaseConfig
+}
+
+func TestBindConfig(t *testing.T) {
+	t.Run("bind config and nested configs", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+		cfg := &appConfig{}
+		err := BindConfig(cnt, cfg, Map(map[string]string{"name": "app", "db.host": "localhost", "cache.ttl": "1m"}))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "app", cfg.Name)
+		assert.Equal(t, databaseConfig{Host: "localhost", Port: 5432, MaxConns: 10, Timeout: 5 * time.Second},
+			cfg.Database)
+		assert.Equal(t, time.Minute, cfg.Cache.TTL)
+
+		var resolved *appConfig
+		cnt.MustResolve(&resolved)
+		assert.Same(t, cfg, resolved)
+
+		cnt.MustBindTransient(func(db *databaseConfig) *repository { return &repository{db: db} })
+		var r *repository
+		cnt.MustResolve(&r)
+		assert.Same(t, &cfg.Database, r.db)
+
+		var cache *cacheConfig
+		cnt.MustResolve(&cache)
+		assert.Same(t, cfg.Cache, cache)
+	})
+
+	t.Run("later sources override earlier ones", func(t *testing.T) {
+		path := writeTestFile(t, "config.json", `{"name": "file", "db": {"host": "file-host", "port": 6432}}`)
+		assert.NoError(t, os.Setenv("TESTAPP_DB_HOST", "env-host"))
+		t.Cleanup(func() { _ = os.Unsetenv("TESTAPP_DB_HOST") })
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Int("db.max_conns", 0, "")
+		fs.String("name", "flag-default", "")
+		assert.NoError(t, fs.Parse([]string{"-db.max_conns=20"}))
+
+		cfg := &appConfig{}
+		err := Load(cfg, Map(map[string]string{"name": "default", "debug": "true"}), File(path), Env("TESTAPP"),
+			Flags(fs))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "file", cfg.Name)
+		assert.True(t, cfg.Debug)
+		assert.Equal(t, "env-host", cfg.Database.Host)
+		assert.Equal(t, 6432, cfg.Database.Port)
+		assert.Equal(t, 20, cfg.Database.MaxConns)
+	})
+
+	t.Run("required fields are validated", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+		err := BindConfig(cnt, &appConfig{})
+		assert.True(t, errors.Is(err, ErrRequired))
+		assert.Contains(t, err.Error(), "db.host")
+
+		var cfg *appConfig
+		assert.True(t, errors.Is(cnt.Resolve(&cfg), ioc.ErrNotRegistered))
+		assert.NoError(t, Load(&appConfig{Database: databaseConfig{Host: "preset"}}))
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		err := Load(&appConfig{}, Map(map[string]string{"db.host": "localhost", "db.port": "abc"}))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `can't set config db.port from value "abc"`)
+	})
+
+	t.Run("unexported embedded non struct field is skipped", func(t *testing.T) {
+		cfg := &embeddedConfig{}
+		assert.NoError(t, Load(cfg, Map(map[string]string{"port": "80", "host": "localhost"})))
+		assert.Equal(t, port(0), cfg.port)
+		assert.Equal(t, 80, cfg.Port)
+		assert.Equal(t, "localhost", cfg.Host)
+	})
+
+	t.Run("config must be pointer to struct", func(t *testing.T) {
+		assert.True(t, errors.Is(Load(appConfig{}), ErrInvalidConfig))
+		assert.True(t, errors.Is(Load((*appConfig)(nil)), ErrInvalidConfig))
+	})
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Name":     "name",
+		"MaxConns": "max_conns",
+		"HTTPPort": "http_port",
+		"DB":       "db",
+		"OAuth2ID": "o_auth2_id",
+	} {
+		assert.Equal(t, expected, snakeCase(name))
+	}
+}
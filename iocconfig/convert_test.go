@@ -0,0 +1,52 @@
+package iocconfig
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetValue(t *testing.T) {
+	port := 8080
+	for _, tt := range []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{name: "string", value: "app", expected: "app"},
+		{name: "bool", value: "true", expected: true},
+		{name: "int", value: "-5", expected: -5},
+		{name: "int8", value: "127", expected: int8(127)},
+		{name: "uint", value: "5", expected: uint(5)},
+		{name: "float", value: "1.5", expected: 1.5},
+		{name: "duration", value: "1m30s", expected: 90 * time.Second},
+		{name: "time", value: "2020-01-02T03:04:05Z", expected: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{name: "pointer", value: "8080", expected: &port},
+		{name: "slice", value: "1, 2,3", expected: []int{1, 2, 3}},
+		{name: "empty slice", value: "", expected: []string{}},
+		{name: "text unmarshaler", value: "127.0.0.1", expected: net.ParseIP("127.0.0.1")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			v := reflect.New(reflect.TypeOf(tt.expected)).Elem()
+			if assert.NoError(t, setValue(v, tt.value)) {
+				assert.Equal(t, tt.expected, v.Interface())
+			}
+		})
+	}
+
+	t.Run("unparsable value", func(t *testing.T) {
+		var i int8
+		assert.Error(t, setValue(reflect.ValueOf(&i).Elem(), "128"))
+		var d time.Duration
+		assert.Error(t, setValue(reflect.ValueOf(&d).Elem(), "5"))
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		var m map[string]string
+		assert.True(t, errors.Is(setValue(reflect.ValueOf(&m).Elem(), "a=b"), ErrUnsupportedType))
+	})
+}
@@ -0,0 +1,14 @@
+module github.com/josephsalimin/go-simple-ioc/iocconfig
+
+go 1.14
+
+require (
+	github.com/BurntSushi/toml v0.3.1
+	github.com/josephsalimin/go-simple-ioc v0.0.0-20261018174004-b1035a40ddda
+	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// Required version of the root module is used by modules that depend on iocconfig, while the root module of this
+// repository is used to develop and test iocconfig.
+replace github.com/josephsalimin/go-simple-ioc => ../
@@ -0,0 +1,28 @@
+package iocconfig
+
+import "reflect"
+
+// provider is ioc.ConfigProvider of sources loaded by BindConfig.
+type provider struct {
+	l *loader
+}
+
+// LookupConfig returns value of key from sources, or default tag of config field with the key.
+func (p *provider) LookupConfig(key string) (string, bool) {
+	if value, ok := p.l.lookup(key); ok {
+		return value, true
+	}
+	value, ok := p.l.defaults[key]
+
+	return value, ok
+}
+
+// ConvertConfig converts value into t, the same way as config fields are set.
+func (p *provider) ConvertConfig(value string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if err := setValue(v, value); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return v, nil
+}
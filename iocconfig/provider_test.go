@@ -0,0 +1,56 @@
+package iocconfig
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type pool struct {
+	maxConns int           `ioc:"config:db.max_conns"`
+	timeout  time.Duration `ioc:"config:db.timeout"`
+	name     string        `ioc:"config:pool.name,default=main"`
+}
+
+func newPool(maxConns int, timeout time.Duration, name string) *pool {
+	return &pool{maxConns: maxConns, timeout: timeout, name: name}
+}
+
+func TestProvider(t *testing.T) {
+	t.Run("inject config from sources and defaults", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+		err := BindConfig(cnt, &appConfig{}, Map(map[string]string{"db.host": "localhost", "db.max_conns": "20"}))
+		if !assert.NoError(t, err) {
+			return
+		}
+		cnt.MustBindSingleton(newPool)
+
+		var p *pool
+		cnt.MustResolve(&p)
+		assert.Equal(t, 20, p.maxConns)
+		assert.Equal(t, 5*time.Second, p.timeout)
+		assert.Equal(t, "main", p.name)
+	})
+
+	t.Run("unparsable config", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+		err := BindConfig(cnt, &appConfig{}, Map(map[string]string{"db.host": "localhost"}),
+			Map(map[string]string{"db.timeout": "soon"}))
+		assert.Error(t, err)
+
+		cnt = ioc.CreateContainer()
+		assert.NoError(t, BindConfig(cnt, &struct{}{}, Map(map[string]string{"db.max_conns": "many"})))
+		cnt.MustBindSingleton(newPool)
+		var p *pool
+		err = cnt.Resolve(&p)
+		assert.Contains(t, err.Error(), `can't convert config db.max_conns value "many" into int`)
+
+		cnt = ioc.CreateContainer()
+		assert.NoError(t, BindConfig(cnt, &struct{}{}))
+		cnt.MustBindSingleton(newPool)
+		assert.True(t, errors.Is(cnt.Resolve(&p), ioc.ErrConfigNotSet))
+	})
+}
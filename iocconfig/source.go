@@ -0,0 +1,184 @@
+package iocconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedFile is returned when extension of config file is not .json, .yaml, .yml or .toml.
+var ErrUnsupportedFile = errors.New("unsupported config file")
+
+// Source is a layer of configuration values, keyed by dotted path of config struct fields, such as db.max_conns.
+type Source interface {
+	// Load reads values of the source, it is called once by BindConfig before any Lookup.
+	Load() error
+	// Lookup returns value of key, ok is false if the source doesn't set it.
+	Lookup(key string) (value string, ok bool)
+}
+
+type mapSource map[string]string
+
+// Map creates Source of given values, such as defaults computed by the application.
+func Map(values map[string]string) Source {
+	return mapSource(values)
+}
+
+func (s mapSource) Load() error {
+	return nil
+}
+
+func (s mapSource) Lookup(key string) (string, bool) {
+	value, ok := s[key]
+	return value, ok
+}
+
+type fileSource struct {
+	path   string
+	values map[string]string
+}
+
+// File creates Source that reads JSON, YAML or TOML file, chosen by extension of path.
+// Nested objects are flattened into dotted keys, and lists of values are joined with comma.
+func File(path string) Source {
+	return &fileSource{path: path}
+}
+
+func (s *fileSource) Load() error {
+	content, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("can't read config file %v, err: %w", s.path, err)
+	}
+
+	var decoded map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(s.path)); ext {
+	case ".json":
+		err = json.Unmarshal(content, &decoded)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &decoded)
+	case ".toml":
+		err = toml.Unmarshal(content, &decoded)
+	default:
+		return fmt.Errorf("can't read config file %v with extension %q, err: %w", s.path, ext, ErrUnsupportedFile)
+	}
+	if err != nil {
+		return fmt.Errorf("can't decode config file %v, err: %w", s.path, err)
+	}
+
+	s.values = map[string]string{}
+	flatten(s.values, "", decoded)
+
+	return nil
+}
+
+func (s *fileSource) Lookup(key string) (string, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// flatten puts value decoded from config file into values, with keys of nested objects joined by dot.
+func flatten(values map[string]string, key string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for k, nested := range v {
+			flatten(values, joinKey(key, k), nested)
+		}
+	case string:
+		values[key] = v
+	case float64:
+		values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		values[key] = v.Format(time.RFC3339Nano)
+	default:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice {
+			values[key] = fmt.Sprint(value)
+			return
+		}
+
+		items := make([]string, 0, rv.Len())
+		for idx := 0; idx < rv.Len(); idx++ {
+			item := map[string]string{}
+			flatten(item, "", rv.Index(idx).Interface())
+			if scalar, ok := item[""]; ok && len(item) == 1 {
+				items = append(items, scalar)
+				continue
+			}
+			for k, v := range item {
+				values[joinKey(joinKey(key, strconv.Itoa(idx)), k)] = v
+			}
+		}
+		if len(items) > 0 || rv.Len() == 0 {
+			values[key] = strings.Join(items, ",")
+		}
+	}
+}
+
+type envSource struct {
+	prefix string
+}
+
+// Env creates Source that reads environment variables named after upper cased key with given prefix, and dots
+// replaced by underscore, such as APP_DB_MAX_CONNS for key db.max_conns and prefix APP.
+func Env(prefix string) Source {
+	return &envSource{prefix: prefix}
+}
+
+func (s *envSource) Load() error {
+	return nil
+}
+
+func (s *envSource) Lookup(key string) (string, bool) {
+	return os.LookupEnv(envName(s.prefix, key))
+}
+
+// envName returns name of environment variable of key with given prefix.
+func envName(prefix, key string) string {
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(key)
+	if prefix != "" {
+		name = strings.TrimSuffix(prefix, "_") + "_" + name
+	}
+
+	return strings.ToUpper(name)
+}
+
+type flagSource struct {
+	fs     *flag.FlagSet
+	values map[string]string
+}
+
+// Flags creates Source of flags named after keys, such as -db.max_conns, that are set in parsed fs.
+// Flags that are not set on the command line are skipped, so their defaults don't override other sources.
+func Flags(fs *flag.FlagSet) Source {
+	return &flagSource{fs: fs}
+}
+
+func (s *flagSource) Load() error {
+	if !s.fs.Parsed() {
+		return fmt.Errorf("can't load flags of %v, err: flag set is not parsed", s.fs.Name())
+	}
+
+	s.values = map[string]string{}
+	s.fs.Visit(func(f *flag.Flag) {
+		s.values[f.Name] = f.Value.String()
+	})
+
+	return nil
+}
+
+func (s *flagSource) Lookup(key string) (string, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
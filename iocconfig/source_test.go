@@ -0,0 +1,104 @@
+package iocconfig
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "iocconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestFile(t *testing.T) {
+	expected := map[string]string{
+		"name":         "app",
+		"db.host":      "localhost",
+		"db.port":      "5432",
+		"db.timeout":   "1.5",
+		"tags":         "a,b",
+		"servers.0.id": "1",
+	}
+
+	for name, content := range map[string]string{
+		"config.json": `{"name": "app", "db": {"host": "localhost", "port": 5432, "timeout": 1.5}, "tags": ["a", "b"],
+			"servers": [{"id": 1}]}`,
+		"config.yaml": "name: app\ndb:\n  host: localhost\n  port: 5432\n  timeout: 1.5\ntags: [a, b]\n" +
+			"servers:\n  - id: 1\n",
+		"config.toml": "name = \"app\"\ntags = [\"a\", \"b\"]\n[db]\nhost = \"localhost\"\nport = 5432\n" +
+			"timeout = 1.5\n[[servers]]\nid = 1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			source := File(writeTestFile(t, name, content))
+			if !assert.NoError(t, source.Load()) {
+				return
+			}
+			assert.Equal(t, expected, source.(*fileSource).values)
+		})
+	}
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		err := File(writeTestFile(t, "config.ini", "name=app")).Load()
+		assert.True(t, errors.Is(err, ErrUnsupportedFile))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := File(filepath.Join(os.TempDir(), "iocconfig-missing.json")).Load()
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		assert.Error(t, File(writeTestFile(t, "config.json", "{")).Load())
+	})
+}
+
+func TestEnv(t *testing.T) {
+	assert.Equal(t, "APP_DB_MAX_CONNS", envName("APP", "db.max_conns"))
+	assert.Equal(t, "APP_DB_MAX_CONNS", envName("APP_", "db.max_conns"))
+	assert.Equal(t, "DB_MAX_CONNS", envName("", "db.max-conns"))
+
+	assert.NoError(t, os.Setenv("TESTENV_NAME", "app"))
+	t.Cleanup(func() { _ = os.Unsetenv("TESTENV_NAME") })
+	value, ok := Env("TESTENV").Lookup("name")
+	assert.True(t, ok)
+	assert.Equal(t, "app", value)
+	_, ok = Env("TESTENV").Lookup("missing")
+	assert.False(t, ok)
+}
+
+func TestFlags(t *testing.T) {
+	t.Run("only set flags", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("name", "default", "")
+		fs.Int("db.port", 0, "")
+		assert.NoError(t, fs.Parse([]string{"-db.port", "5432"}))
+
+		source := Flags(fs)
+		assert.NoError(t, source.Load())
+		value, ok := source.Lookup("db.port")
+		assert.True(t, ok)
+		assert.Equal(t, "5432", value)
+		_, ok = source.Lookup("name")
+		assert.False(t, ok)
+	})
+
+	t.Run("flag set must be parsed", func(t *testing.T) {
+		assert.Error(t, Flags(flag.NewFlagSet("test", flag.ContinueOnError)).Load())
+	})
+}
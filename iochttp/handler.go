@@ -0,0 +1,74 @@
+package iochttp
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ErrorRenderer writes response for error that happens while resolving or calling handler function.
+type ErrorRenderer func(w http.ResponseWriter, r *http.Request, err error)
+
+// DefaultErrorRenderer responds with 500 status code without exposing the error.
+func DefaultErrorRenderer(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+type handlerOption struct {
+	errorRenderer ErrorRenderer
+}
+
+type HandlerOption func(o *handlerOption)
+
+// WithErrorRenderer replaces DefaultErrorRenderer.
+func WithErrorRenderer(errorRenderer ErrorRenderer) HandlerOption {
+	return func(o *handlerOption) {
+		o.errorRenderer = errorRenderer
+	}
+}
+
+func applyHandlerOption(o *handlerOption, opts []HandlerOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// Handler creates http.Handler from function whose parameters are resolved from request scope for each call,
+// e.g. func(w http.ResponseWriter, r *http.Request, svc UserService). Scope of Middleware is used if the request
+// has one, otherwise a new scope is created from c and disposed once the function returns.
+// The function may returns error, which will be rendered the same way as resolve error.
+// Will panic if fn is not a function or has other outputs.
+func Handler(c ioc.Container, fn interface{}, opts ...HandlerOption) http.Handler {
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		panic(fmt.Errorf("expected function, but instead got %v", fnType))
+	}
+	if fnType.NumOut() > 1 || (fnType.NumOut() == 1 && fnType.Out(0) != errorType) {
+		panic(fmt.Errorf("expected function without output or with error output, but instead got %v", fnType))
+	}
+
+	o := &handlerOption{errorRenderer: DefaultErrorRenderer}
+	applyHandlerOption(o, opts)
+	installModule(c)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Scope created by Middleware is reused, so scoped instances set up by earlier middleware are shared.
+		scope, ok := ScopeFrom(r.Context())
+		if !ok {
+			scope, r = newRequestScope(c, w, r)
+			defer func() { _ = scope.Dispose() }()
+		}
+
+		outputs, err := scope.CallContext(r.Context(), fn)
+		if err == nil && len(outputs) > 0 && outputs[0] != nil {
+			err = outputs[0].(error)
+		}
+		if err != nil {
+			o.errorRenderer(w, r, err)
+		}
+	})
+}
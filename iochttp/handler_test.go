@@ -0,0 +1,96 @@
+package iochttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type userService interface {
+	Name() string
+}
+
+type staticUserService struct{}
+
+func (s *staticUserService) Name() string {
+	return "user"
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("inject dependencies to handler", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+
+		cnt.MustBindSingleton(func() userService { return &staticUserService{} })
+		cnt.MustBindScoped(func(r *http.Request) *requestInfo { return &requestInfo{path: r.URL.Path} })
+
+		handler := Handler(cnt, func(w http.ResponseWriter, r *http.Request, svc userService, info *requestInfo) {
+			_, _ = w.Write([]byte(svc.Name() + " " + info.path))
+		})
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "user /users", w.Body.String())
+	})
+
+	t.Run("render resolve error", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+
+		handler := Handler(cnt, func(w http.ResponseWriter, svc userService) {
+			t.Fatalf("should not be called")
+		})
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("render handler error with custom renderer", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+
+		errHandler := errors.New("handler error")
+		handler := Handler(cnt, func(w http.ResponseWriter) error {
+			return errHandler
+		}, WithErrorRenderer(func(w http.ResponseWriter, r *http.Request, err error) {
+			assert.True(t, errors.Is(err, errHandler))
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("use scope of middleware", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+
+		cnt.MustBindScoped(func() *requestInfo { return &requestInfo{} })
+		auth := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var info *requestInfo
+				assert.NoError(t, Resolve(r, &info))
+				info.path = "alice"
+				next.ServeHTTP(w, r)
+			})
+		}
+		handler := Middleware(cnt)(auth(Handler(cnt, func(w http.ResponseWriter, info *requestInfo) {
+			_, _ = w.Write([]byte("user=" + info.path))
+		})))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "user=alice", w.Body.String())
+	})
+
+	t.Run("handler with invalid output", func(t *testing.T) {
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+
+		Handler(ioc.CreateContainer(), func(w http.ResponseWriter) int { return 0 })
+	})
+}
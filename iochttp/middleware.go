@@ -0,0 +1,80 @@
+// Package iochttp integrates ioc container with net/http by creating a scope for each request.
+package iochttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+var (
+	ErrNoScope   = errors.New("request does not have ioc scope")
+	ErrNoRequest = errors.New("request information is only available inside request scope")
+)
+
+type scopeContextKey struct{}
+
+// Module binds placeholders of request information, so the container graph can be validated before any request
+// comes. The placeholders are replaced by actual request information in each request scope.
+var Module = &ioc.Module{
+	Name: "iochttp",
+	Bindings: []ioc.Binding{
+		ioc.Scoped(func() (*http.Request, error) { return nil, ErrNoRequest }),
+		ioc.Scoped(func() (http.ResponseWriter, error) { return nil, ErrNoRequest }),
+	},
+}
+
+func installModule(c ioc.Container) {
+	if err := c.Install(Module); err != nil && !errors.Is(err, ioc.ErrModuleInstalled) {
+		panic(err)
+	}
+}
+
+// newRequestScope creates scope from container and binds request information to it.
+// Returned request carries the scope in its context.
+func newRequestScope(c ioc.Container, w http.ResponseWriter, r *http.Request) (ioc.Scope, *http.Request) {
+	scope := c.CreateScope()
+	r = r.WithContext(context.WithValue(r.Context(), scopeContextKey{}, scope))
+
+	scope.MustBindSingleton(func() *http.Request { return r })
+	scope.MustBindSingleton(func() http.ResponseWriter { return w })
+	scope.MustBindSingleton(func() context.Context { return r.Context() })
+
+	return scope, r
+}
+
+// Middleware creates scope from c for each request, binds *http.Request, http.ResponseWriter and request
+// context.Context to it, and disposes the scope once the handler returns.
+// The scope can be fetched from request context using ScopeFrom or used directly with Resolve.
+func Middleware(c ioc.Container) func(http.Handler) http.Handler {
+	installModule(c)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope, r := newRequestScope(c, w, r)
+			// Nothing can be done about dispose error once the response is written.
+			defer func() { _ = scope.Dispose() }()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ScopeFrom returns request scope saved in ctx by Middleware.
+func ScopeFrom(ctx context.Context) (ioc.Scope, bool) {
+	scope, ok := ctx.Value(scopeContextKey{}).(ioc.Scope)
+	return scope, ok
+}
+
+// Resolve resolves given receiver from scope of the request using request context.
+// Will returns ErrNoScope if the request does not pass through Middleware.
+func Resolve(r *http.Request, receiver interface{}, opts ...ioc.ResolveOption) error {
+	scope, ok := ScopeFrom(r.Context())
+	if !ok {
+		return ErrNoScope
+	}
+
+	return scope.ResolveContext(r.Context(), receiver, opts...)
+}
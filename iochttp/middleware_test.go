@@ -0,0 +1,85 @@
+package iochttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type requestInfo struct {
+	path string
+}
+
+type closableRequestInfo struct {
+	closed *bool
+}
+
+func (i *closableRequestInfo) Close() error {
+	*i.closed = true
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("resolve request information from scope", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+
+		cnt.MustBindScoped(func(r *http.Request, w http.ResponseWriter) *requestInfo {
+			w.Header().Set("X-Path", r.URL.Path)
+			return &requestInfo{path: r.URL.Path}
+		})
+
+		handler := Middleware(cnt)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var first, second *requestInfo
+			assert.NoError(t, Resolve(r, &first))
+			assert.NoError(t, Resolve(r, &second))
+			assert.Equal(t, "/users", first.path)
+			assert.True(t, first == second)
+
+			var ctx context.Context
+			assert.NoError(t, Resolve(r, &ctx))
+			scope, ok := ScopeFrom(ctx)
+			assert.True(t, ok)
+			assert.NotNil(t, scope)
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		assert.Equal(t, "/users", w.Header().Get("X-Path"))
+		assert.NoError(t, cnt.Validate())
+	})
+
+	t.Run("dispose scope after handler returns", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+
+		closed := false
+		cnt.MustBindScoped(func() *closableRequestInfo { return &closableRequestInfo{closed: &closed} })
+
+		handler := Middleware(cnt)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var info *closableRequestInfo
+			assert.NoError(t, Resolve(r, &info))
+			assert.False(t, closed)
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.True(t, closed)
+	})
+
+	t.Run("request information is not available outside request", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+		Middleware(cnt)
+
+		var r *http.Request
+		assert.True(t, errors.Is(cnt.CreateScope().Resolve(&r), ErrNoRequest))
+	})
+
+	t.Run("resolve without middleware", func(t *testing.T) {
+		var info *requestInfo
+		err := Resolve(httptest.NewRequest(http.MethodGet, "/", nil), &info)
+		assert.True(t, errors.Is(err, ErrNoScope))
+	})
+}
@@ -0,0 +1,160 @@
+// Package iocmetrics collects resolve metrics of ioc container, and exports them in Prometheus text format.
+package iocmetrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+// DefaultBuckets is upper bounds in seconds of constructor duration histogram, used when NewRegistry is called
+// without buckets.
+var DefaultBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+type bindingKey struct {
+	label    string
+	alias    string
+	lifetime string
+}
+
+type bindingMetrics struct {
+	resolves  uint64
+	cacheHits uint64
+	instances uint64
+	// buckets is count of constructions per bucket of Registry, not cumulative.
+	buckets []uint64
+	sum     float64
+}
+
+// Registry is ioc.Metrics that keeps metrics of each binding in memory.
+type Registry struct {
+	mu       sync.Mutex
+	buckets  []float64
+	bindings map[bindingKey]*bindingMetrics
+}
+
+var _ ioc.Metrics = (*Registry)(nil)
+
+// NewRegistry creates empty Registry with given upper bounds in seconds of constructor duration histogram,
+// DefaultBuckets is used if buckets is empty.
+func NewRegistry(buckets ...float64) *Registry {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Registry{buckets: buckets, bindings: map[bindingKey]*bindingMetrics{}}
+}
+
+func (r *Registry) binding(label, alias, lifetime string) *bindingMetrics {
+	key := bindingKey{label: label, alias: alias, lifetime: lifetime}
+	m, ok := r.bindings[key]
+	if !ok {
+		m = &bindingMetrics{buckets: make([]uint64, len(r.buckets))}
+		r.bindings[key] = m
+	}
+
+	return m
+}
+
+func (r *Registry) IncResolve(label, alias, lifetime string, cached bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.binding(label, alias, lifetime)
+	m.resolves++
+	if cached {
+		m.cacheHits++
+	}
+}
+
+func (r *Registry) ObserveConstruct(label, alias, lifetime string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.binding(label, alias, lifetime)
+	m.instances++
+	m.sum += duration.Seconds()
+	if idx := sort.SearchFloat64s(r.buckets, duration.Seconds()); idx < len(r.buckets) {
+		m.buckets[idx]++
+	}
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (k bindingKey) labels() string {
+	return fmt.Sprintf(`type="%v",alias="%v",lifetime="%v"`, labelValueReplacer.Replace(k.label),
+		labelValueReplacer.Replace(k.alias), labelValueReplacer.Replace(k.lifetime))
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// WriteTo writes metrics of all bindings to w in Prometheus text format, ordered by type, alias and lifetime.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	keys := make([]bindingKey, 0, len(r.bindings))
+	snapshot := make(map[bindingKey]bindingMetrics, len(r.bindings))
+	for key, m := range r.bindings {
+		keys = append(keys, key)
+		copied := *m
+		copied.buckets = append([]uint64(nil), m.buckets...)
+		snapshot[key] = copied
+	}
+	r.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].label != keys[j].label {
+			return keys[i].label < keys[j].label
+		}
+		if keys[i].alias != keys[j].alias {
+			return keys[i].alias < keys[j].alias
+		}
+		return keys[i].lifetime < keys[j].lifetime
+	})
+
+	var b strings.Builder
+	counters := []struct {
+		name, help string
+		value      func(m bindingMetrics) uint64
+	}{
+		{"ioc_resolves_total", "Number of resolves of each binding.",
+			func(m bindingMetrics) uint64 { return m.resolves }},
+		{"ioc_cache_hits_total", "Number of resolves that return saved singleton or scoped instance.",
+			func(m bindingMetrics) uint64 { return m.cacheHits }},
+		{"ioc_instances_created_total", "Number of instances created by resolve function of each binding.",
+			func(m bindingMetrics) uint64 { return m.instances }},
+	}
+	for _, counter := range counters {
+		fmt.Fprintf(&b, "# HELP %v %v\n# TYPE %v counter\n", counter.name, counter.help, counter.name)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%v{%v} %v\n", counter.name, key.labels(), counter.value(snapshot[key]))
+		}
+	}
+
+	const histogram = "ioc_constructor_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %v Duration of resolve function calls, including dependencies.\n", histogram)
+	fmt.Fprintf(&b, "# TYPE %v histogram\n", histogram)
+	for _, key := range keys {
+		m := snapshot[key]
+		var cumulative uint64
+		for idx, upper := range r.buckets {
+			cumulative += m.buckets[idx]
+			fmt.Fprintf(&b, "%v_bucket{%v,le=\"%v\"} %v\n", histogram, key.labels(), formatFloat(upper), cumulative)
+		}
+		fmt.Fprintf(&b, "%v_bucket{%v,le=\"+Inf\"} %v\n", histogram, key.labels(), m.instances)
+		fmt.Fprintf(&b, "%v_sum{%v} %v\n", histogram, key.labels(), formatFloat(m.sum))
+		fmt.Fprintf(&b, "%v_count{%v} %v\n", histogram, key.labels(), m.instances)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
@@ -0,0 +1,103 @@
+package iocmetrics
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type config struct{}
+
+type service struct {
+	config *config
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("collect metrics of container", func(t *testing.T) {
+		registry := NewRegistry()
+		cnt := ioc.CreateContainer(ioc.WithMetrics(registry))
+		cnt.MustBindSingleton(func() *config { return &config{} })
+		cnt.MustBindTransient(func(c *config) *service { return &service{config: c} }, ioc.WithBindAlias("svc"))
+
+		var s *service
+		cnt.MustResolve(&s, ioc.WithResolveAlias("svc"))
+		cnt.MustResolve(&s, ioc.WithResolveAlias("svc"))
+
+		var buf bytes.Buffer
+		n, err := registry.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		out := buf.String()
+		assert.Contains(t, out, "# TYPE ioc_resolves_total counter\n"+
+			`ioc_resolves_total{type="*iocmetrics.config",alias="default",lifetime="singleton"} 2`+"\n"+
+			`ioc_resolves_total{type="*iocmetrics.service",alias="svc",lifetime="transient"} 2`+"\n")
+		assert.Contains(t, out,
+			`ioc_cache_hits_total{type="*iocmetrics.config",alias="default",lifetime="singleton"} 1`+"\n")
+		assert.Contains(t, out,
+			`ioc_instances_created_total{type="*iocmetrics.config",alias="default",lifetime="singleton"} 1`+"\n")
+		assert.Contains(t, out,
+			`ioc_instances_created_total{type="*iocmetrics.service",alias="svc",lifetime="transient"} 2`+"\n")
+		assert.Contains(t, out, "# TYPE ioc_constructor_duration_seconds histogram\n")
+		assert.Contains(t, out, `ioc_constructor_duration_seconds_bucket{type="*iocmetrics.service",alias="svc",`+
+			`lifetime="transient",le="+Inf"} 2`+"\n")
+		assert.Contains(t, out, `ioc_constructor_duration_seconds_count{type="*iocmetrics.service",alias="svc",`+
+			`lifetime="transient"} 2`+"\n")
+	})
+
+	t.Run("failed resolve is not counted as instance", func(t *testing.T) {
+		registry := NewRegistry()
+		cnt := ioc.CreateContainer(ioc.WithMetrics(registry))
+		cnt.MustBindTransient(func() (*config, error) { return nil, errors.New("failed") })
+
+		var c *config
+		assert.Error(t, cnt.Resolve(&c))
+
+		var buf bytes.Buffer
+		_, err := registry.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(),
+			`ioc_resolves_total{type="*iocmetrics.config",alias="default",lifetime="transient"} 1`+"\n")
+		assert.Contains(t, buf.String(),
+			`ioc_instances_created_total{type="*iocmetrics.config",alias="default",lifetime="transient"} 0`+"\n")
+	})
+
+	t.Run("histogram buckets are cumulative", func(t *testing.T) {
+		registry := NewRegistry(1, 0.1)
+		registry.ObserveConstruct("a", "default", "transient", 50*time.Millisecond)
+		registry.ObserveConstruct("a", "default", "transient", 500*time.Millisecond)
+		registry.ObserveConstruct("a", "default", "transient", 2*time.Second)
+
+		var buf bytes.Buffer
+		_, err := registry.WriteTo(&buf)
+		assert.NoError(t, err)
+		var lines []string
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if strings.HasPrefix(line, "ioc_constructor_duration_seconds") {
+				lines = append(lines, line)
+			}
+		}
+		assert.Equal(t, []string{
+			`ioc_constructor_duration_seconds_bucket{type="a",alias="default",lifetime="transient",le="0.1"} 1`,
+			`ioc_constructor_duration_seconds_bucket{type="a",alias="default",lifetime="transient",le="1"} 2`,
+			`ioc_constructor_duration_seconds_bucket{type="a",alias="default",lifetime="transient",le="+Inf"} 3`,
+			`ioc_constructor_duration_seconds_sum{type="a",alias="default",lifetime="transient"} 2.55`,
+			`ioc_constructor_duration_seconds_count{type="a",alias="default",lifetime="transient"} 3`,
+		}, lines)
+	})
+
+	t.Run("escape label values", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.IncResolve("a", "say \"hi\"\\\n", "transient", false)
+
+		var buf bytes.Buffer
+		_, err := registry.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), `ioc_resolves_total{type="a",alias="say \"hi\"\\\n",lifetime="transient"} 1`)
+	})
+}
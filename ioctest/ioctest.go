@@ -0,0 +1,55 @@
+// Package ioctest provides helpers to replace bindings of ioc container in tests.
+package ioctest
+
+import (
+	"testing"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+// Override replaces binding of returned type of factory in c until the test and its subtests finish.
+// Singletons of c that depend on the replaced binding are resolved again with the factory, and again with the
+// original binding once it is restored.
+func Override(t testing.TB, c ioc.Container, factory interface{}, opts ...ioc.BindOption) {
+	t.Helper()
+
+	restore, err := c.Override(factory, opts...)
+	if err != nil {
+		t.Fatalf("ioctest: failed to override binding, err: %v", err)
+	}
+	t.Cleanup(restore)
+}
+
+type option struct {
+	autoStub bool
+}
+
+type Option func(o *option)
+
+// WithAutoStub binds stub from types registered by RegisterStub for interface dependencies that are not bound,
+// instead of failing with ErrNotRegistered or ErrAliasNotKnown. Each alias gets its own stub instance, which can be
+// programmed and checked using StubOf with the returned container.
+func WithAutoStub() Option {
+	return func(o *option) {
+		o.autoStub = true
+	}
+}
+
+// NewContainerFrom creates new container with bindings of c, so test can bind or override without affecting c.
+// Singletons are instantiated again in the new container.
+func NewContainerFrom(c ioc.Container, opts ...Option) ioc.Container {
+	o := &option{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if !o.autoStub {
+		return c.Clone()
+	}
+
+	r := &stubRegistry{stubs: map[interface{}]*Stub{}}
+	clone := c.Clone(ioc.WithMissingHandler(r.autoStub))
+	clone.MustBindSingleton(func() *stubRegistry { return r })
+
+	return clone
+}
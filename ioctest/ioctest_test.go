@@ -0,0 +1,84 @@
+package ioctest
+
+import (
+	"testing"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type repository interface {
+	Name() string
+}
+
+type realRepository struct{}
+
+func (*realRepository) Name() string { return "real" }
+
+type fakeRepository struct{}
+
+func (*fakeRepository) Name() string { return "fake" }
+
+type service struct {
+	repository repository
+}
+
+func newContainer() ioc.Container {
+	cnt := ioc.CreateContainer()
+	cnt.MustBindSingleton(func() repository { return &realRepository{} })
+	cnt.MustBindSingleton(func(r repository) *service { return &service{repository: r} })
+
+	return cnt
+}
+
+func TestOverride(t *testing.T) {
+	t.Run("override and restore singleton dependency", func(t *testing.T) {
+		cnt := newContainer()
+		var s *service
+		cnt.MustResolve(&s)
+		assert.Equal(t, "real", s.repository.Name())
+
+		t.Run("overridden", func(t *testing.T) {
+			Override(t, cnt, func() repository { return &fakeRepository{} })
+
+			var s *service
+			cnt.MustResolve(&s)
+			assert.Equal(t, "fake", s.repository.Name())
+		})
+
+		cnt.MustResolve(&s)
+		assert.Equal(t, "real", s.repository.Name())
+	})
+
+	t.Run("override unbound type is removed after test", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+
+		t.Run("overridden", func(t *testing.T) {
+			Override(t, cnt, func() repository { return &fakeRepository{} })
+
+			var r repository
+			cnt.MustResolve(&r)
+			assert.Equal(t, "fake", r.Name())
+		})
+
+		var r repository
+		assert.Error(t, cnt.Resolve(&r))
+	})
+}
+
+func TestNewContainerFrom(t *testing.T) {
+	cnt := newContainer()
+	var original *service
+	cnt.MustResolve(&original)
+
+	clone := NewContainerFrom(cnt)
+	Override(t, clone, func() repository { return &fakeRepository{} })
+
+	var s *service
+	clone.MustResolve(&s)
+	assert.Equal(t, "fake", s.repository.Name())
+	assert.NotSame(t, original, s)
+
+	cnt.MustResolve(&s)
+	assert.Same(t, original, s)
+}
@@ -0,0 +1,168 @@
+package ioctest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+// funcFieldSuffix is suffix of stub func field that implements method of the same name.
+const funcFieldSuffix = "Func"
+
+// Stub records calls of stub methods, and returns values programmed with Return.
+type Stub struct {
+	mu      sync.Mutex
+	calls   map[string][][]interface{}
+	returns map[string][]interface{}
+}
+
+// Return programs method to return given values, method that is not programmed returns zero values.
+func (s *Stub) Return(method string, values ...interface{}) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.returns[method] = values
+
+	return s
+}
+
+// Calls returns arguments of each call of method, ordered by call.
+func (s *Stub) Calls(method string) [][]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([][]interface{}(nil), s.calls[method]...)
+}
+
+// call records args of method and builds its results from programmed values.
+func (s *Stub) call(method string, fnType reflect.Type, args []reflect.Value) []reflect.Value {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Interface())
+	}
+	s.calls[method] = append(s.calls[method], values)
+
+	results := make([]reflect.Value, fnType.NumOut())
+	for idx := range results {
+		results[idx] = reflect.New(fnType.Out(idx)).Elem()
+		if idx >= len(s.returns[method]) || s.returns[method][idx] == nil {
+			continue
+		}
+
+		value := reflect.ValueOf(s.returns[method][idx])
+		if !value.Type().AssignableTo(fnType.Out(idx)) {
+			panic(fmt.Sprintf("ioctest: return value %v of %v is not assignable to %v", idx, method,
+				fnType.Out(idx)))
+		}
+		results[idx].Set(value)
+	}
+
+	return results
+}
+
+var (
+	stubsMu sync.Mutex
+	// stubTypes is map of interface type to type of its registered stub.
+	stubTypes = map[reflect.Type]reflect.Type{}
+)
+
+// stubRegistry is bound to container created with WithAutoStub, and maps stub instance created for the container to
+// its Stub, so the stubs are released together with the container.
+type stubRegistry struct {
+	mu    sync.Mutex
+	stubs map[interface{}]*Stub
+}
+
+// RegisterStub registers type of stub to be used by WithAutoStub for interface that iface points to, such as
+// RegisterStub((*UserRepository)(nil), new(UserRepositoryStub)). Stub is only used for that exact interface, even if
+// it implements others. Stub must be pointer to struct that implements each method by calling its func field named
+// after the method with Func suffix, such as generated by ioctestgen.
+func RegisterStub(iface interface{}, stub interface{}) {
+	ifaceType := reflect.TypeOf(iface)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("ioctest: expected pointer to interface, but instead got %v", ifaceType))
+	}
+	stubType := reflect.TypeOf(stub)
+	if stubType == nil || stubType.Kind() != reflect.Ptr || stubType.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("ioctest: expected pointer to struct stub, but instead got %v", stubType))
+	}
+	if !stubType.Implements(ifaceType.Elem()) {
+		panic(fmt.Sprintf("ioctest: stub %v does not implement %v", stubType, ifaceType.Elem()))
+	}
+
+	stubsMu.Lock()
+	defer stubsMu.Unlock()
+
+	stubTypes[ifaceType.Elem()] = stubType
+}
+
+// lookupStubType returns stub type registered for interface t, nil if there is none.
+func lookupStubType(t reflect.Type) reflect.Type {
+	stubsMu.Lock()
+	defer stubsMu.Unlock()
+
+	return stubTypes[t]
+}
+
+// newStub creates instance of stub type, with its func fields recording calls to the returned Stub.
+func newStub(stubType reflect.Type) (interface{}, *Stub) {
+	s := &Stub{calls: map[string][][]interface{}{}, returns: map[string][]interface{}{}}
+	instance := reflect.New(stubType.Elem())
+	for idx := 0; idx < stubType.Elem().NumField(); idx++ {
+		field := stubType.Elem().Field(idx)
+		if field.Type.Kind() != reflect.Func || !strings.HasSuffix(field.Name, funcFieldSuffix) {
+			continue
+		}
+
+		method := strings.TrimSuffix(field.Name, funcFieldSuffix)
+		fnType := field.Type
+		instance.Elem().Field(idx).Set(reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+			return s.call(method, fnType, args)
+		}))
+	}
+
+	return instance.Interface(), s
+}
+
+// StubOf returns Stub of instance created by WithAutoStub for container c, to program its return values and check
+// its calls. Returns nil if instance is not created by WithAutoStub for c.
+func StubOf(c ioc.Container, instance interface{}) *Stub {
+	var r *stubRegistry
+	if err := c.Resolve(&r); err != nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.stubs[instance]
+}
+
+// autoStub is ioc.MissingHandler that binds new stub instance for interface with registered stub type, and
+// records the instance to r.
+func (r *stubRegistry) autoStub(t reflect.Type, alias string) (interface{}, bool) {
+	stubType := lookupStubType(t)
+	if stubType == nil {
+		return nil, false
+	}
+
+	resolveFunc := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{t}, false),
+		func([]reflect.Value) []reflect.Value {
+			instance, s := newStub(stubType)
+			r.mu.Lock()
+			r.stubs[instance] = s
+			r.mu.Unlock()
+
+			result := reflect.New(t).Elem()
+			result.Set(reflect.ValueOf(instance))
+			return []reflect.Value{result}
+		})
+
+	return resolveFunc.Interface(), true
+}
@@ -0,0 +1,131 @@
+package ioctest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	name string
+}
+
+type userStore interface {
+	Find(id string) (*user, error)
+	Save(users ...*user)
+}
+
+type userStoreStub struct {
+	FindFunc func(id string) (*user, error)
+	SaveFunc func(users ...*user)
+}
+
+func (s *userStoreStub) Find(p0 string) (*user, error) {
+	return s.FindFunc(p0)
+}
+
+func (s *userStoreStub) Save(p0 ...*user) {
+	s.SaveFunc(p0...)
+}
+
+type unstubbed interface {
+	Unstubbed()
+}
+
+type userFinder interface {
+	Find(id string) (*user, error)
+}
+
+type userService struct {
+	store userStore
+}
+
+func init() {
+	RegisterStub((*userStore)(nil), new(userStoreStub))
+}
+
+func TestWithAutoStub(t *testing.T) {
+	t.Run("stub unbound interface dependency", func(t *testing.T) {
+		cnt := ioc.CreateContainer()
+		cnt.MustBindSingleton(func(s userStore) *userService { return &userService{store: s} })
+
+		var svc *userService
+		assert.True(t, errors.Is(cnt.Resolve(&svc), ioc.ErrNotRegistered))
+
+		cnt = NewContainerFrom(cnt, WithAutoStub())
+		cnt.MustResolve(&svc)
+		var store userStore
+		cnt.MustResolve(&store)
+		assert.Same(t, store, svc.store)
+
+		stub := StubOf(cnt, store)
+		if !assert.NotNil(t, stub) {
+			return
+		}
+		u, err := svc.store.Find("1")
+		assert.Nil(t, u)
+		assert.NoError(t, err)
+
+		expected := &user{name: "stub"}
+		stub.Return("Find", expected, errors.New("not found"))
+		u, err = svc.store.Find("2")
+		assert.Same(t, expected, u)
+		assert.EqualError(t, err, "not found")
+
+		svc.store.Save(expected)
+		assert.Equal(t, [][]interface{}{{"1"}, {"2"}}, stub.Calls("Find"))
+		assert.Equal(t, [][]interface{}{{[]*user{expected}}}, stub.Calls("Save"))
+	})
+
+	t.Run("stub each alias separately", func(t *testing.T) {
+		cnt := NewContainerFrom(ioc.CreateContainer(), WithAutoStub())
+
+		var store, other userStore
+		cnt.MustResolve(&store)
+		cnt.MustResolve(&other, ioc.WithResolveAlias("other"))
+		assert.NotSame(t, store, other)
+		assert.NoError(t, cnt.Validate())
+	})
+
+	t.Run("interface without registered stub is still missing", func(t *testing.T) {
+		cnt := NewContainerFrom(ioc.CreateContainer(), WithAutoStub())
+
+		var u unstubbed
+		assert.True(t, errors.Is(cnt.Resolve(&u), ioc.ErrNotRegistered))
+		assert.Nil(t, StubOf(cnt, u))
+	})
+
+	t.Run("stub is only used for its registered interface", func(t *testing.T) {
+		cnt := NewContainerFrom(ioc.CreateContainer(), WithAutoStub())
+
+		var finder userFinder
+		assert.True(t, errors.Is(cnt.Resolve(&finder), ioc.ErrNotRegistered))
+		var value interface{}
+		assert.True(t, errors.Is(cnt.Resolve(&value), ioc.ErrNotRegistered))
+	})
+
+	t.Run("stubs are kept per container", func(t *testing.T) {
+		cnt := NewContainerFrom(ioc.CreateContainer(), WithAutoStub())
+		other := NewContainerFrom(ioc.CreateContainer(), WithAutoStub())
+
+		var store userStore
+		cnt.MustResolve(&store)
+		assert.NotNil(t, StubOf(cnt, store))
+		assert.NotNil(t, StubOf(cnt.CreateScope(), store))
+		assert.Nil(t, StubOf(other, store))
+		assert.Nil(t, StubOf(ioc.CreateContainer(), store))
+	})
+
+	t.Run("return value with wrong type", func(t *testing.T) {
+		cnt := NewContainerFrom(ioc.CreateContainer(), WithAutoStub())
+
+		var store userStore
+		cnt.MustResolve(&store)
+		StubOf(cnt, store).Return("Find", "not user")
+		assert.Panics(t, func() {
+			_, _ = store.Find("1")
+		})
+	})
+}
@@ -0,0 +1,115 @@
+package ioctrace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// ConstructorTiming is time spent calling resolve function of a binding, cached resolves are not counted.
+type ConstructorTiming struct {
+	Type  reflect.Type
+	Alias string
+	// Calls is number of times the resolve function is called.
+	Calls int
+	// Total includes time spent resolving dependencies, while Self excludes it.
+	Total time.Duration
+	Self  time.Duration
+}
+
+type timingKey struct {
+	t     reflect.Type
+	alias string
+}
+
+// frame is resolve in progress, keyed by Profiler in resolve context.
+type frame struct {
+	parent *frame
+	// dependencies is total duration of resolving dependencies of the binding.
+	dependencies time.Duration
+}
+
+// Profiler is ioc.Observer that collects time spent in each resolve function, to report the slowest constructors.
+type Profiler struct {
+	mu      sync.Mutex
+	timings map[timingKey]*ConstructorTiming
+}
+
+// NewProfiler creates empty Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{timings: map[timingKey]*ConstructorTiming{}}
+}
+
+func (p *Profiler) OnResolveStart(ctx context.Context, t reflect.Type, alias string) context.Context {
+	parent, _ := ctx.Value(p).(*frame)
+	return context.WithValue(ctx, p, &frame{parent: parent})
+}
+
+func (p *Profiler) OnResolveEnd(ctx context.Context, t reflect.Type, alias string, duration time.Duration,
+	cached bool, err error) {
+	f, ok := ctx.Value(p).(*frame)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if f.parent != nil {
+		f.parent.dependencies += duration
+	}
+	if cached {
+		return
+	}
+
+	key := timingKey{t: t, alias: alias}
+	timing, ok := p.timings[key]
+	if !ok {
+		timing = &ConstructorTiming{Type: t, Alias: alias}
+		p.timings[key] = timing
+	}
+	timing.Calls++
+	timing.Total += duration
+	timing.Self += duration - f.dependencies
+}
+
+// Slowest returns at most n constructors ordered by their Self duration, all of them if n is not positive.
+func (p *Profiler) Slowest(n int) []ConstructorTiming {
+	p.mu.Lock()
+	timings := make([]ConstructorTiming, 0, len(p.timings))
+	for _, timing := range p.timings {
+		timings = append(timings, *timing)
+	}
+	p.mu.Unlock()
+
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].Self != timings[j].Self {
+			return timings[i].Self > timings[j].Self
+		}
+		if timings[i].Type.String() != timings[j].Type.String() {
+			return timings[i].Type.String() < timings[j].Type.String()
+		}
+		return timings[i].Alias < timings[j].Alias
+	})
+	if n > 0 && len(timings) > n {
+		timings = timings[:n]
+	}
+
+	return timings
+}
+
+// WriteReport writes table of at most n slowest constructors to w, all of them if n is not positive.
+func (p *Profiler) WriteReport(w io.Writer, n int) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tALIAS\tCALLS\tSELF\tTOTAL")
+	for _, timing := range p.Slowest(n) {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", timing.Type, timing.Alias, timing.Calls, timing.Self, timing.Total)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,49 @@
+package ioctrace
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiler(t *testing.T) {
+	t.Run("report slowest constructors", func(t *testing.T) {
+		profiler := NewProfiler()
+		cnt := ioc.CreateContainer(ioc.WithObserver(profiler))
+		cnt.MustBindSingleton(func() *config {
+			time.Sleep(20 * time.Millisecond)
+			return &config{}
+		})
+		cnt.MustBindTransient(func(c *config) *repository { return &repository{config: c} })
+
+		var r *repository
+		cnt.MustResolve(&r)
+		cnt.MustResolve(&r)
+
+		timings := profiler.Slowest(0)
+		if !assert.Len(t, timings, 2) {
+			return
+		}
+		assert.Equal(t, reflect.TypeOf(&config{}), timings[0].Type)
+		assert.Equal(t, 1, timings[0].Calls)
+		assert.True(t, timings[0].Self >= 20*time.Millisecond)
+		assert.Equal(t, reflect.TypeOf(&repository{}), timings[1].Type)
+		assert.Equal(t, 2, timings[1].Calls)
+		assert.True(t, timings[1].Total >= 20*time.Millisecond)
+		assert.True(t, timings[1].Self < timings[1].Total)
+		assert.Len(t, profiler.Slowest(1), 1)
+
+		var buf bytes.Buffer
+		assert.NoError(t, profiler.WriteReport(&buf, 1))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if assert.Len(t, lines, 2) {
+			assert.True(t, strings.HasPrefix(lines[0], "TYPE"))
+			assert.True(t, strings.HasPrefix(lines[1], "*ioctrace.config"))
+		}
+	})
+}
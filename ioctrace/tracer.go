@@ -0,0 +1,124 @@
+// Package ioctrace turns resolve notifications of ioc container into nested spans, and reports the slowest
+// constructors.
+package ioctrace
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+// Span is a unit of work started by Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans, it can be implemented as adapter of in-process tracing library, or Recorder in tests.
+type Tracer interface {
+	// Start starts span with given name as child of parent, parent is nil for root span.
+	Start(parent Span, name string) Span
+}
+
+type observer struct {
+	tracer Tracer
+}
+
+// NewObserver creates ioc.Observer that starts span for each resolve, nested under span of the binding that depends
+// on it. Span is named "ioc.resolve" followed by the type, with ioc.type, ioc.alias and ioc.cached attributes.
+func NewObserver(tracer Tracer) ioc.Observer {
+	return &observer{tracer: tracer}
+}
+
+// OnResolveStart starts span as child of span in ctx, and returns ctx with the span keyed by the observer.
+func (o *observer) OnResolveStart(ctx context.Context, t reflect.Type, alias string) context.Context {
+	parent, _ := ctx.Value(o).(Span)
+	span := o.tracer.Start(parent, "ioc.resolve "+t.String())
+	span.SetAttribute("ioc.type", t.String())
+	span.SetAttribute("ioc.alias", alias)
+
+	return context.WithValue(ctx, o, span)
+}
+
+func (o *observer) OnResolveEnd(ctx context.Context, t reflect.Type, alias string, duration time.Duration,
+	cached bool, err error) {
+	span, ok := ctx.Value(o).(Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttribute("ioc.cached", cached)
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// RecordedSpan is span recorded by Recorder.
+type RecordedSpan struct {
+	Name       string
+	Parent     *RecordedSpan
+	Attributes map[string]interface{}
+	Err        error
+	StartTime  time.Time
+	EndTime    time.Time
+
+	recorder *Recorder
+}
+
+func (s *RecordedSpan) SetAttribute(key string, value interface{}) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.Attributes[key] = value
+}
+
+func (s *RecordedSpan) RecordError(err error) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.Err = err
+}
+
+func (s *RecordedSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.EndTime = time.Now()
+}
+
+// Recorder is in-memory Tracer that keeps every started span.
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// NewRecorder creates empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Start(parent Span, name string) Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	span := &RecordedSpan{Name: name, Attributes: map[string]interface{}{}, StartTime: time.Now(), recorder: r}
+	if parent, ok := parent.(*RecordedSpan); ok {
+		span.Parent = parent
+	}
+	r.spans = append(r.spans, span)
+
+	return span
+}
+
+// Spans returns recorded spans ordered by their start.
+func (r *Recorder) Spans() []*RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*RecordedSpan(nil), r.spans...)
+}
@@ -0,0 +1,64 @@
+package ioctrace
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+	"github.com/stretchr/testify/assert"
+)
+
+type config struct{}
+
+type repository struct {
+	config *config
+}
+
+type service struct {
+	repository *repository
+}
+
+func TestNewObserver(t *testing.T) {
+	t.Run("record nested spans", func(t *testing.T) {
+		recorder := NewRecorder()
+		cnt := ioc.CreateContainer(ioc.WithObserver(NewObserver(recorder)))
+		cnt.MustBindSingleton(func() *config { return &config{} })
+		cnt.MustBindSingleton(func(c *config) *repository { return &repository{config: c} })
+		cnt.MustBindTransient(func(r *repository, c *config) *service { return &service{repository: r} })
+
+		var s *service
+		cnt.MustResolve(&s)
+
+		spans := recorder.Spans()
+		if !assert.Len(t, spans, 4) {
+			return
+		}
+		assert.Equal(t, "ioc.resolve *ioctrace.service", spans[0].Name)
+		assert.Nil(t, spans[0].Parent)
+		assert.Equal(t, "ioc.resolve *ioctrace.repository", spans[1].Name)
+		assert.Same(t, spans[0], spans[1].Parent)
+		assert.Equal(t, "ioc.resolve *ioctrace.config", spans[2].Name)
+		assert.Same(t, spans[1], spans[2].Parent)
+		assert.Equal(t, false, spans[2].Attributes["ioc.cached"])
+		assert.Same(t, spans[0], spans[3].Parent)
+		assert.Equal(t, true, spans[3].Attributes["ioc.cached"])
+		assert.Equal(t, "default", spans[3].Attributes["ioc.alias"])
+		for _, span := range spans {
+			assert.False(t, span.EndTime.Before(span.StartTime))
+		}
+	})
+
+	t.Run("record error", func(t *testing.T) {
+		recorder := NewRecorder()
+		cnt := ioc.CreateContainer(ioc.WithObserver(NewObserver(recorder)))
+		expected := errors.New("failed")
+		cnt.MustBindTransient(func() (*config, error) { return nil, expected })
+
+		var c *config
+		assert.Error(t, cnt.Resolve(&c))
+		spans := recorder.Spans()
+		if assert.Len(t, spans, 1) {
+			assert.True(t, errors.Is(spans[0].Err, expected))
+		}
+	})
+}
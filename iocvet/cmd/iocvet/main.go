@@ -0,0 +1,16 @@
+// Command iocvet runs iocvet analyzer as go vet tool.
+//
+// Usage:
+//
+//	go install github.com/josephsalimin/go-simple-ioc/iocvet/cmd/iocvet
+//	go vet -vettool=$(which iocvet) ./...
+package main
+
+import (
+	"github.com/josephsalimin/go-simple-ioc/iocvet"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(iocvet.Analyzer)
+}
@@ -0,0 +1,265 @@
+// Package iocvet provides analyzer that reports binding mistakes which the ioc container would only report at runtime.
+//
+// It can be run with go vet using iocvet/cmd/iocvet:
+//
+//	go vet -vettool=$(which iocvet) ./...
+package iocvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	iocPath      = "github.com/josephsalimin/go-simple-ioc/ioc"
+	structTagKey = "ioc"
+	defaultAlias = "default"
+	// configAliasPrefix marks ioc tag of field injected from configuration instead of binding.
+	configAliasPrefix = "config:"
+)
+
+const doc = `check mistakes in ioc bindings
+
+The iocvet analyzer reports:
+  - resolve function that does not return pointer or interface, or has second output other than error
+  - WithBindMeta value that is not a pointer or does not implement returned interface
+  - Resolve receiver that is not a pointer
+  - ioc tag alias of meta struct that is never bound in the package or its dependencies`
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "iocvet",
+	Doc:       doc,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(boundAliases)},
+}
+
+// boundAliases is package fact of type and alias pairs bound in the package and its dependencies.
+type boundAliases struct {
+	Keys []string
+}
+
+func (*boundAliases) AFact() {}
+
+func (f *boundAliases) String() string {
+	return "bound(" + strings.Join(f.Keys, ", ") + ")"
+}
+
+func aliasKey(t types.Type, alias string) string {
+	return types.TypeString(t, nil) + "#" + alias
+}
+
+var bindFuncs = map[string]bool{
+	"BindSingleton": true, "MustBindSingleton": true,
+	"BindTransient": true, "MustBindTransient": true,
+	"BindScoped": true, "MustBindScoped": true,
+	"Singleton": true, "Transient": true, "Scoped": true,
+}
+
+var resolveFuncs = map[string]int{
+	"Resolve": 0, "MustResolve": 0, "ResolveContext": 1,
+}
+
+// iocCallee returns name of ioc function or method called by call, empty if it is not from ioc package.
+func iocCallee(info *types.Info, call *ast.CallExpr) string {
+	obj := typeutil.Callee(info, call)
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != iocPath {
+		return ""
+	}
+
+	return obj.Name()
+}
+
+type bindCall struct {
+	call   *ast.CallExpr
+	fn     *types.Signature
+	result types.Type
+	meta   types.Type
+	alias  string
+}
+
+func stringValue(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// checkBind reports mistakes of resolve function and meta, and returns parsed bind call.
+func checkBind(pass *analysis.Pass, call *ast.CallExpr) *bindCall {
+	if len(call.Args) == 0 {
+		return nil
+	}
+	fn, ok := pass.TypesInfo.TypeOf(call.Args[0]).(*types.Signature)
+	if !ok {
+		pass.Reportf(call.Args[0].Pos(), "resolve function must be a function, but instead got %v",
+			pass.TypesInfo.TypeOf(call.Args[0]))
+		return nil
+	}
+
+	results := fn.Results()
+	if results.Len() == 0 {
+		pass.Reportf(call.Args[0].Pos(), "resolve function must return pointer or interface")
+		return nil
+	}
+	if results.Len() > 2 || (results.Len() == 2 && !types.Identical(results.At(1).Type(),
+		types.Universe.Lookup("error").Type())) {
+		pass.Reportf(call.Args[0].Pos(), "second output of resolve function must be error, but instead got %v",
+			results.At(1).Type())
+	}
+
+	b := &bindCall{call: call, fn: fn, result: results.At(0).Type(), alias: defaultAlias}
+	switch b.result.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+	default:
+		pass.Reportf(call.Args[0].Pos(), "resolve function must return pointer or interface, but instead got %v",
+			b.result)
+		return nil
+	}
+
+	for _, arg := range call.Args[1:] {
+		opt, ok := arg.(*ast.CallExpr)
+		if !ok || len(opt.Args) != 1 {
+			continue
+		}
+
+		switch iocCallee(pass.TypesInfo, opt) {
+		case "WithBindAlias":
+			if alias, ok := stringValue(pass.TypesInfo, opt.Args[0]); ok {
+				b.alias = alias
+			}
+		case "WithBindMeta":
+			b.meta = pass.TypesInfo.TypeOf(opt.Args[0])
+			iface, isInterface := b.result.Underlying().(*types.Interface)
+			if !isInterface {
+				continue
+			}
+			if _, ok := b.meta.Underlying().(*types.Pointer); !ok {
+				pass.Reportf(opt.Args[0].Pos(), "meta must be a pointer, but instead got %v", b.meta)
+				b.meta = nil
+				continue
+			}
+			if !types.Implements(b.meta, iface) {
+				pass.Reportf(opt.Args[0].Pos(), "meta %v does not implement %v", b.meta, b.result)
+				b.meta = nil
+			}
+		}
+	}
+
+	return b
+}
+
+// checkResolve reports Resolve receiver that is not a pointer, with suggested fix to take its address.
+func checkResolve(pass *analysis.Pass, call *ast.CallExpr, receiverIdx int) {
+	if len(call.Args) <= receiverIdx {
+		return
+	}
+	receiver := call.Args[receiverIdx]
+	if _, ok := pass.TypesInfo.TypeOf(receiver).Underlying().(*types.Pointer); ok {
+		return
+	}
+
+	diagnostic := analysis.Diagnostic{
+		Pos:     receiver.Pos(),
+		End:     receiver.End(),
+		Message: "resolve receiver must be a pointer, but instead got " + pass.TypesInfo.TypeOf(receiver).String(),
+	}
+	if _, ok := receiver.(*ast.Ident); ok {
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Take address of the receiver",
+			TextEdits: []analysis.TextEdit{{Pos: receiver.Pos(), End: receiver.Pos(), NewText: []byte("&")}},
+		}}
+	}
+	pass.Report(diagnostic)
+}
+
+// checkTags reports ioc tag alias of meta struct fields that are used as dependency but never bound.
+func checkTags(pass *analysis.Pass, b *bindCall, bound map[string]bool) {
+	meta := b.meta
+	if meta == nil {
+		meta = b.result
+	}
+	if ptr, ok := meta.Underlying().(*types.Pointer); ok {
+		meta = ptr.Elem()
+	}
+	st, ok := meta.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	params := b.fn.Params()
+	for idx := 0; idx < st.NumFields(); idx++ {
+		field := st.Field(idx)
+		alias := strings.Split(reflect.StructTag(st.Tag(idx)).Get(structTagKey), ",")[0]
+		if alias == "" || alias == defaultAlias || strings.HasPrefix(alias, configAliasPrefix) {
+			continue
+		}
+
+		for paramIdx := 0; paramIdx < params.Len(); paramIdx++ {
+			if !types.Identical(params.At(paramIdx).Type(), field.Type()) {
+				continue
+			}
+			if !bound[aliasKey(field.Type(), alias)] {
+				pass.Reportf(b.call.Pos(), "ioc tag alias %q of field %v is never bound for %v", alias, field.Name(),
+					field.Type())
+			}
+			break
+		}
+	}
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var binds []*bindCall
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		name := iocCallee(pass.TypesInfo, call)
+		if bindFuncs[name] {
+			if b := checkBind(pass, call); b != nil {
+				binds = append(binds, b)
+			}
+		}
+		if receiverIdx, ok := resolveFuncs[name]; ok {
+			checkResolve(pass, call, receiverIdx)
+		}
+	})
+
+	bound := map[string]bool{}
+	for _, b := range binds {
+		bound[aliasKey(b.result, b.alias)] = true
+	}
+	for _, fact := range pass.AllPackageFacts() {
+		if aliases, ok := fact.Fact.(*boundAliases); ok {
+			for _, key := range aliases.Keys {
+				bound[key] = true
+			}
+		}
+	}
+
+	for _, b := range binds {
+		checkTags(pass, b, bound)
+	}
+
+	if len(bound) > 0 {
+		keys := make([]string, 0, len(bound))
+		for key := range bound {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pass.ExportPackageFact(&boundAliases{Keys: keys})
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,14 @@
+package iocvet
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "a")
+	analysistest.Run(t, testdata, Analyzer, "app")
+}
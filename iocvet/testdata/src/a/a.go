@@ -0,0 +1,64 @@
+package a // want package:`bound\(\*a.Config#default, \*a.Config#service_cfg, \*a.unbound#default, a.Service#default\)`
+
+import (
+	"context"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+type Config struct{}
+
+type Service interface {
+	Name() string
+}
+
+type service struct {
+	cfg *Config `ioc:"service_cfg"`
+}
+
+func (s *service) Name() string { return "service" }
+
+type other struct{}
+
+type unbound struct {
+	cfg *Config `ioc:"unknown"`
+}
+
+func (u *unbound) Name() string { return "unbound" }
+
+func bind() {
+	ioc.MustBindSingleton(func() *Config { return &Config{} }, ioc.WithBindAlias("service_cfg"))
+	ioc.MustBindSingleton(func() Config { return Config{} }) // want `resolve function must return pointer or interface, but instead got a.Config`
+	ioc.MustBindSingleton(func() (*Config, int) { return nil, 0 }) // want `second output of resolve function must be error, but instead got int`
+	ioc.MustBindSingleton(func() {}) // want `resolve function must return pointer or interface`
+
+	ioc.MustBindSingleton(func(cfg *Config) Service {
+		return &service{cfg: cfg}
+	}, ioc.WithBindMeta(&service{}))
+	ioc.MustBindTransient(func(cfg *Config) Service {
+		return &service{cfg: cfg}
+	}, ioc.WithBindMeta(service{})) // want `meta must be a pointer, but instead got a.service`
+	ioc.MustBindTransient(func(cfg *Config) Service {
+		return &service{cfg: cfg}
+	}, ioc.WithBindMeta(&other{})) // want `meta \*a.other does not implement a.Service`
+
+	c := ioc.CreateContainer()
+	c.MustBindSingleton(func(cfg *Config) Service { // want `ioc tag alias "unknown" of field cfg is never bound for \*a.Config`
+		return &unbound{cfg: cfg}
+	}, ioc.WithBindMeta(&unbound{}))
+	_ = ioc.Singleton(func(cfg *Config) *unbound { // want `ioc tag alias "unknown" of field cfg is never bound for \*a.Config`
+		return &unbound{cfg: cfg}
+	})
+}
+
+func resolve() {
+	var svc Service
+	ioc.MustBindSingleton(func() *Config { return &Config{} })
+	_ = ioc.Resolve(&svc)
+	_ = ioc.Resolve(svc) // want `resolve receiver must be a pointer, but instead got a.Service`
+	_ = ioc.ResolveContext(context.Background(), svc) // want `resolve receiver must be a pointer, but instead got a.Service`
+
+	c := ioc.CreateContainer()
+	c.MustResolve(svc) // want `resolve receiver must be a pointer, but instead got a.Service`
+	c.MustResolve(service{}) // want `resolve receiver must be a pointer, but instead got a.service`
+}
@@ -0,0 +1,20 @@
+package app // want package:`bound\(\*app.service#default, \*lib.Config#primary\)`
+
+import (
+	"lib"
+
+	"github.com/josephsalimin/go-simple-ioc/ioc"
+)
+
+type service struct {
+	cfg   *lib.Config `ioc:"primary"`
+	other *lib.Config `ioc:"secondary"`
+	port  int         `ioc:"config:http.port,default=8080"`
+}
+
+func Bind() {
+	lib.Bind()
+	ioc.MustBindSingleton(func(cfg, other *lib.Config, port int) *service { // want `ioc tag alias "secondary" of field other is never bound for \*lib.Config`
+		return &service{cfg: cfg, other: other, port: port}
+	})
+}
@@ -0,0 +1,36 @@
+// Package ioc is a stub of the real ioc package, only declares what iocvet looks for.
+package ioc
+
+import "context"
+
+type BindOption func()
+
+type ResolveOption func()
+
+type Binding struct{}
+
+type Container interface {
+	BindSingleton(interface{}, ...BindOption) error
+	MustBindSingleton(interface{}, ...BindOption)
+	BindTransient(interface{}, ...BindOption) error
+	MustBindTransient(interface{}, ...BindOption)
+	Resolve(interface{}, ...ResolveOption) error
+	ResolveContext(context.Context, interface{}, ...ResolveOption) error
+	MustResolve(interface{}, ...ResolveOption)
+}
+
+func CreateContainer() Container { return nil }
+
+func WithBindAlias(alias string) BindOption { return nil }
+
+func WithBindMeta(meta interface{}) BindOption { return nil }
+
+func MustBindSingleton(resolveFunc interface{}, opts ...BindOption) {}
+
+func MustBindTransient(resolveFunc interface{}, opts ...BindOption) {}
+
+func Singleton(resolveFunc interface{}, opts ...BindOption) Binding { return Binding{} }
+
+func Resolve(receiver interface{}, opts ...ResolveOption) error { return nil }
+
+func ResolveContext(ctx context.Context, receiver interface{}, opts ...ResolveOption) error { return nil }
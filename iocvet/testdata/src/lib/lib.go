@@ -0,0 +1,9 @@
+package lib
+
+import "github.com/josephsalimin/go-simple-ioc/ioc"
+
+type Config struct{}
+
+func Bind() {
+	ioc.MustBindSingleton(func() *Config { return &Config{} }, ioc.WithBindAlias("primary"))
+}